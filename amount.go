@@ -0,0 +1,57 @@
+package mtparser
+
+import (
+	"errors"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var amountFormat = regexp.MustCompile("^[0-9]+,[0-9]*$|^[0-9]+$")
+
+// Money is an exact amount in a currency.
+type Money struct {
+	Currency string
+	Value    *big.Rat
+}
+
+// ParseAmount converts an amount written with a decimal comma, such as
+// "1101,50", into an exact rational number.
+func ParseAmount(s string) (*big.Rat, error) {
+	if !amountFormat.MatchString(s) {
+		return nil, errors.New("Invalid amount '" + s + "'")
+	}
+	r, ok := new(big.Rat).SetString(strings.Replace(strings.TrimSuffix(s, ","), ",", ".", 1))
+	if !ok {
+		return nil, errors.New("Invalid amount '" + s + "'")
+	}
+	return r, nil
+}
+
+// ParseDate converts a YYMMDD date.
+func ParseDate(s string) (time.Time, error) {
+	return time.Parse("060102", s)
+}
+
+func money(ccy string, amt string) (Money, error) {
+	val, err := ParseAmount(amt)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Currency: ccy, Value: val}, nil
+}
+
+// FormatAmount writes an amount with a decimal comma and no more decimals
+// than needed, the inverse of ParseAmount.
+func FormatAmount(r *big.Rat) string {
+	d := 0
+	for p := new(big.Rat).Set(r); !p.IsInt() && d < 15; d++ {
+		p.Mul(p, big.NewRat(10, 1))
+	}
+	s := strings.Replace(r.FloatString(d), ".", ",", 1)
+	if d == 0 {
+		s += ","
+	}
+	return s
+}
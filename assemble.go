@@ -0,0 +1,233 @@
+package mtparser
+
+import (
+	"errors"
+	"iter"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MissingPagesError is yielded by AssembleStatements for a statement whose
+// pages were not all found. Final is set when the page closing with a 62F
+// balance was never seen, in which case Missing only lists the gaps found.
+// Final is never set for MT942, which has no closing balance.
+type MissingPagesError struct {
+	Account         string
+	StatementNumber string
+	Missing         []int
+	Final           bool
+}
+
+func (e *MissingPagesError) Error() string {
+	msg := "Statement " + e.StatementNumber + " of account " + e.Account + " is missing"
+	var pgs []string
+	for _, p := range e.Missing {
+		pgs = append(pgs, strconv.Itoa(p))
+	}
+	switch len(pgs) {
+	case 0:
+	case 1:
+		msg += " page " + pgs[0]
+	default:
+		msg += " pages " + strings.Join(pgs, ", ")
+	}
+	if e.Final {
+		if len(pgs) > 0 {
+			msg += " and"
+		}
+		msg += " its final page"
+	}
+	return msg
+}
+
+type statementPages struct {
+	pgs  map[int]*Statement
+	last int
+	// interim is set for MT942 pages, which carry no balances.
+	interim bool
+}
+
+// AssembleStatements groups statement pages by message type, reference,
+// account and statement number and yields one statement once its final page
+// and every page before it have been seen. Pages are ordered by the sequence
+// number of field 28C and the intermediate balances of consecutive pages must
+// agree. A page seen twice is reported as an error and the first one is kept.
+// Statements that are still incomplete when msgs ends are yielded with a
+// MissingPagesError. Messages that are not statements are skipped.
+//
+// A MT942 has no closing balance to mark its final page. A MT942 without a
+// sequence number stands alone, and numbered MT942 pages are yielded once
+// msgs ends if none are missing.
+func AssembleStatements(msgs iter.Seq2[*Message, error]) iter.Seq2[*Statement, error] {
+	return func(yield func(*Statement, error) bool) {
+		grps := map[string]*statementPages{}
+
+		for msg, err := range msgs {
+			if err != nil && msg == nil {
+				if !yield(nil, err) {
+					return
+				}
+				continue
+			}
+			if !slices.Contains(statementTypes, msg.Map["2"]["type"].Val) {
+				continue
+			}
+
+			pg, err := NewStatement(msg)
+			if err != nil {
+				if !yield(nil, err) {
+					return
+				}
+				continue
+			}
+
+			seq := 1
+			if len(pg.SequenceNumber) > 0 {
+				if seq, err = strconv.Atoi(pg.SequenceNumber); err != nil {
+					if !yield(nil, errors.New("Invalid sequence number '"+pg.SequenceNumber+"'")) {
+						return
+					}
+					continue
+				}
+			}
+
+			key := pg.Type + "/" + pg.Reference + "/" + pg.Account + "/" + pg.StatementNumber
+			grp, ok := grps[key]
+			if !ok {
+				grp = &statementPages{pgs: map[int]*Statement{}, interim: pg.Type == "942"}
+				grps[key] = grp
+			}
+			if _, ok := grp.pgs[seq]; ok {
+				if !yield(nil, errors.New("Page "+strconv.Itoa(seq)+" of statement "+pg.StatementNumber+" of account "+pg.Account+" was seen twice")) {
+					return
+				}
+				continue
+			}
+			grp.pgs[seq] = pg
+			if pg.Closing != nil && !pg.Closing.Intermediate() {
+				grp.last = seq
+			}
+			if grp.interim && len(pg.SequenceNumber) == 0 {
+				grp.last = seq
+			}
+
+			if grp.last > 0 && len(grp.missing()) == 0 {
+				delete(grps, key)
+				if !yield(grp.merge()) {
+					return
+				}
+			}
+		}
+
+		keys := make([]string, 0, len(grps))
+		for k := range grps {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+
+		for _, k := range keys {
+			grp := grps[k]
+			if grp.interim && len(grp.missing()) == 0 {
+				for seq := range grp.pgs {
+					grp.last = max(grp.last, seq)
+				}
+				if !yield(grp.merge()) {
+					return
+				}
+				continue
+			}
+
+			var pg *Statement
+			for _, p := range grp.pgs {
+				pg = p
+				break
+			}
+			err := &MissingPagesError{
+				Account:         pg.Account,
+				StatementNumber: pg.StatementNumber,
+				Missing:         grp.missing(),
+				Final:           grp.last == 0 && !grp.interim,
+			}
+			if !yield(nil, err) {
+				return
+			}
+		}
+	}
+}
+
+// missing lists the sequence numbers absent before the last page seen.
+func (g *statementPages) missing() []int {
+	end := g.last
+	if end == 0 {
+		for seq := range g.pgs {
+			end = max(end, seq)
+		}
+	}
+
+	var mis []int
+	for seq := 1; seq <= end; seq++ {
+		if _, ok := g.pgs[seq]; !ok {
+			mis = append(mis, seq)
+		}
+	}
+	return mis
+}
+
+// merge joins complete pages into one statement and checks that the
+// intermediate balances carry over from page to page.
+func (g *statementPages) merge() (*Statement, error) {
+	var errs []error
+
+	fst, lst := g.pgs[1], g.pgs[g.last]
+	st := *fst
+	st.Lines = nil
+	st.SequenceNumber = ""
+	st.Pages = g.last
+
+	if fst.Opening != nil && fst.Opening.Intermediate() {
+		errs = append(errs, errors.New("First page opens with an intermediate balance"))
+	}
+
+	for seq := 1; seq <= g.last; seq++ {
+		pg := g.pgs[seq]
+		if seq > 1 && !g.interim && !sameBalance(g.pgs[seq-1].Closing, pg.Opening) {
+			errs = append(errs, errors.New("Page "+strconv.Itoa(seq)+" does not open with the closing balance of page "+strconv.Itoa(seq-1)))
+		}
+		st.Lines = append(st.Lines, pg.Lines...)
+		if seq > 1 {
+			st.Debits = addSummary(st.Debits, pg.Debits)
+			st.Credits = addSummary(st.Credits, pg.Credits)
+		}
+	}
+
+	st.Closing = lst.Closing
+	st.ClosingAvailable = lst.ClosingAvailable
+	st.ForwardAvailable = lst.ForwardAvailable
+	st.Information = lst.Information
+	st.DateTime = lst.DateTime
+
+	return &st, errors.Join(errs...)
+}
+
+// addSummary adds the 90C or 90D summary of a MT942 page, which only covers
+// the entries of that page.
+func addSummary(a *EntrySummary, b *EntrySummary) *EntrySummary {
+	if a == nil || b == nil || a.Currency != b.Currency {
+		if a == nil {
+			return b
+		}
+		return a
+	}
+	sum := &EntrySummary{Count: a.Count + b.Count, Money: Money{Currency: a.Currency}}
+	sum.Value = new(big.Rat).Add(a.Value, b.Value)
+	return sum
+}
+
+func sameBalance(a *Balance, b *Balance) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	return a.Mark == b.Mark && a.Currency == b.Currency && a.Value.Cmp(b.Value) == 0
+}
@@ -1,50 +1,78 @@
 package mtparser
 
-import "errors"
+import "text/scanner"
 
 func (s *Parser) scanBlocks() error {
-	var blk Block
-
 	mp := map[string]Node{}
 	s.blk.Val = []Block{}
 	bin := len(s.Blocks)
 
 	for i := 0; i <= 100; i++ {
-		if s.Scan() != '{' {
-			return errors.New(s.ErrMessage('{', true))
-		}
-
-		s.Scan()
-		blk.Key = s.TokenText()
-
-		if s.Scan() != ':' {
-			return errors.New(s.ErrMessage(':', true))
-		}
-
-		blk.Val = ""
-		if s.Scan() != '}' {
-			blk.Val = s.TokenText()
-
-			if s.Scan() != '}' {
-				return errors.New(s.ErrMessage('}', true))
+		blk, open, err := s.scanSubBlock()
+		if err != nil {
+			if err = s.tolerate(err); err != nil {
+				return err
 			}
+			s.skipTo("{}")
+			if open && s.Peek() == '}' {
+				s.Next()
+			} else if open {
+				// The sub-block ran into the next block, so the enclosing
+				// one was never closed either.
+				s.Map[s.blk.Key] = mp
+				return s.errUnclosed(ErrUnclosedBlock, '}')
+			}
+		} else {
+			mp[blk.Key] = Node{
+				Val: blk.Val.(string),
+				Blk: bin,
+				Ind: i,
+			}
+			s.blk.Val = append(s.blk.Val.([]Block), *&blk)
 		}
 
-		mp[blk.Key] = Node{
-			Val: blk.Val.(string),
-			Blk: bin,
-			Ind: i,
-		}
-		s.blk.Val = append(s.blk.Val.([]Block), *&blk)
-
-		if s.Peek() == '}' {
+		if s.Peek() == '}' || s.Peek() == scanner.EOF {
 			break
 		}
 		if i == 100 {
-			return errors.New("Unclosed block, expected }")
+			return s.errUnclosed(ErrUnclosedBlock, '}')
 		}
 	}
 
 	s.Map[s.blk.Key] = mp
 	return nil
 }
+
+// scanSubBlock scans one {key:value} block. On error open reports whether
+// the block was left unclosed.
+func (s *Parser) scanSubBlock() (blk Block, open bool, err error) {
+	if p := s.Peek(); p != '{' {
+		return blk, false, s.errExpected('{', p)
+	}
+	s.Next()
+
+	switch p := s.Peek(); p {
+	case '{', '}', ':', '\n', scanner.EOF:
+		return blk, true, s.errUnexpected(p)
+	}
+	s.Scan()
+	blk.Key = s.TokenText()
+
+	if p := s.Peek(); p != ':' {
+		return blk, true, s.errExpected(':', p)
+	}
+	s.Next()
+
+	blk.Val = ""
+	if s.Peek() != '}' {
+		s.Scan()
+		blk.Val = s.TokenText()
+
+		if p := s.Peek(); p != '}' {
+			return blk, true, s.errExpected('}', p)
+		}
+	}
+	s.Next()
+
+	return blk, false, nil
+}
@@ -1,50 +1,35 @@
 package mtparser
 
-import "errors"
+import "text/scanner"
 
 func (s *Parser) scanBody() error {
-	var fld Field
-	var p rune
+	var t rune
+	var i2 int
 	mp := map[string]Node{}
 	bin := len(s.Blocks)
 
 	s.blk.Val = []Field{}
 
-	if s.Scan() != '\n' {
-		return errors.New(s.ErrMessage('\n', true))
-	}
-
-	for i2 := 0; s.Peek() != '}'; i2++ {
-
-		if s.Scan() != ':' {
-			return errors.New(s.ErrMessage(':', true))
+	if t = s.Scan(); t != '\n' {
+		if err := s.tolerate(s.errExpected('\n', t)); err != nil {
+			return err
 		}
+		s.skipField(t)
+	}
 
-		s.Scan()
-		fld.Key = s.TokenText()
-
-		if s.Scan() != ':' {
-			return errors.New(s.ErrMessage(':', true))
+	for s.Peek() != '}' && s.Peek() != '{' && s.Peek() != scanner.EOF {
+		if s.Peek() == '-' {
+			s.Scan()
+			break
 		}
 
-		fld.Val = ""
-
-		for i := 0; i <= 100; i++ {
-			t := s.Scan()
-			p = s.Peek()
-
-			if p == '-' && t == '\n' {
-				break
-			}
-			if p == ':' {
-				break
-			}
-
-			fld.Val += s.TokenText()
-
-			if i == 100 {
-				return errors.New("Unclosed body, expected -")
+		fld, t, err := s.scanField()
+		if err != nil {
+			if err = s.tolerate(err); err != nil {
+				return err
 			}
+			s.skipField(t)
+			continue
 		}
 
 		mp[fld.Key] = Node{
@@ -52,14 +37,78 @@ func (s *Parser) scanBody() error {
 			Blk: bin,
 			Ind: i2,
 		}
+		s.Body = append(s.Body, Node{
+			Key: fld.Key,
+			Val: fld.Val,
+			Blk: bin,
+			Ind: i2,
+		})
 		s.blk.Val = append(s.blk.Val.([]Field), *&fld)
+		i2++
+	}
 
-		if p == '-' {
-			s.Scan()
+	s.tag = ""
+	s.Map[s.blk.Key] = mp
+
+	// A block starting on a new line means the text block was never closed.
+	if s.Peek() == '{' {
+		return s.errUnclosed(ErrUnclosedBody, '-')
+	}
+	return nil
+}
+
+// scanField scans one :tag:value field and returns it with the last token
+// scanned.
+func (s *Parser) scanField() (fld Field, t rune, err error) {
+	var p rune
+
+	if t = s.Scan(); t != ':' {
+		return fld, t, s.errExpected(':', t)
+	}
+
+	s.Scan()
+	fld.Key = s.TokenText()
+	s.tag = fld.Key
+
+	if t = s.Scan(); t != ':' {
+		return fld, t, s.errExpected(':', t)
+	}
+
+	for i := 0; i <= 100; i++ {
+		t = s.Scan()
+		p = s.Peek()
+
+		if t == '}' || t == scanner.EOF {
+			return fld, t, s.errUnclosed(ErrUnclosedBody, '-')
+		}
+		if (p == '-' || p == '{') && t == '\n' {
 			break
 		}
+		if p == ':' {
+			break
+		}
+
+		fld.Val += s.TokenText()
+
+		if i == 100 {
+			return fld, t, s.errUnclosed(ErrUnclosedBody, '-')
+		}
 	}
 
-	s.Map[s.blk.Key] = mp
-	return nil
+	return fld, t, nil
+}
+
+// skipField consumes input up to the start of the next field or the end of
+// the text block. t is the last rune consumed.
+func (s *Parser) skipField(t rune) {
+	for {
+		p := s.Peek()
+		if p == scanner.EOF || p == '}' {
+			return
+		}
+		if t == '\n' && (p == ':' || p == '-' || p == '{') {
+			return
+		}
+		t = s.Next()
+	}
 }
@@ -0,0 +1,150 @@
+package mtparser
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"text/scanner"
+)
+
+// Kinds of ParseError, usable with errors.Is.
+var (
+	ErrExpected      = errors.New("expected character")
+	ErrUnexpected    = errors.New("unexpected character")
+	ErrUnclosedBlock = errors.New("unclosed block")
+	ErrUnclosedBody  = errors.New("unclosed body")
+	ErrInvalidHeader = errors.New("invalid header field")
+)
+
+// ParseError describes where and why a message could not be parsed. Offset
+// is the byte offset in the input, counting the carriage returns that are
+// dropped before parsing.
+type ParseError struct {
+	Kind     error
+	Line     int
+	Column   int
+	Offset   int
+	Block    string
+	Tag      string
+	Expected rune
+	Actual   rune
+	Prefix   string
+}
+
+func (e *ParseError) Error() string {
+	var msg string
+
+	switch e.Kind {
+	case ErrUnexpected:
+		msg = "Unexpected '" + string(e.Actual) + "'"
+	case ErrUnclosedBlock:
+		msg = "Unclosed block, expected '" + string(e.Expected) + "'"
+	case ErrUnclosedBody:
+		msg = "Unclosed body, expected '" + string(e.Expected) + "'"
+	case ErrInvalidHeader:
+		msg = "Invalid header field '" + e.Tag + "'"
+	default:
+		msg = "Expected '" + string(e.Expected) + "'"
+	}
+
+	msg += " at line " + strconv.Itoa(e.Line) + " column " + strconv.Itoa(e.Column)
+	if len(e.Prefix) > 0 {
+		msg = e.Prefix + " " + msg
+	}
+	return msg
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Kind
+}
+
+func (s *Parser) newError(kind error, x rune, c rune) *ParseError {
+	pos := s.Pos()
+	if s.cr != nil {
+		pos.Offset = s.cr.offset(pos.Offset)
+	}
+	return &ParseError{
+		Kind:     kind,
+		Line:     pos.Line,
+		Column:   pos.Column,
+		Offset:   pos.Offset,
+		Block:    s.blk.Key,
+		Tag:      s.tag,
+		Expected: x,
+		Actual:   c,
+		Prefix:   s.ErrPrefix,
+	}
+}
+
+// errExpected reports that token t was scanned where x was expected.
+func (s *Parser) errExpected(x rune, t rune) error {
+	if t == scanner.Ident {
+		t = []rune(s.TokenText())[0]
+	}
+	return s.newError(ErrExpected, x, t)
+}
+
+func (s *Parser) errUnexpected(c rune) error {
+	return s.newError(ErrUnexpected, 0, c)
+}
+
+// errInvalid reports a header field whose value is not allowed.
+func (s *Parser) errInvalid(key string, val string) error {
+	e := s.newError(ErrInvalidHeader, 0, []rune(val + " ")[0])
+	e.Tag = key
+	return e
+}
+
+func (s *Parser) errUnclosed(kind error, x rune) error {
+	return s.newError(kind, x, s.Peek())
+}
+
+// ErrorList is returned by Parse in tolerant mode and holds every error
+// found in the input.
+type ErrorList []*ParseError
+
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	}
+	return l[0].Error() + " (and " + strconv.Itoa(len(l)-1) + " more errors)"
+}
+
+func (l ErrorList) Unwrap() []error {
+	errs := make([]error, len(l))
+	for i, e := range l {
+		errs[i] = e
+	}
+	return errs
+}
+
+// Err returns nil when the list is empty and the list otherwise.
+func (l ErrorList) Err() error {
+	if len(l) == 0 {
+		return nil
+	}
+	return l
+}
+
+// tolerate records err when the parser is tolerant and returns nil,
+// otherwise err is returned unchanged.
+func (s *Parser) tolerate(err error) error {
+	if !s.Tolerant || err == nil {
+		return err
+	}
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		s.errs = append(s.errs, pe)
+	}
+	return nil
+}
+
+// skipTo consumes input until the next rune is one of stop.
+func (s *Parser) skipTo(stop string) {
+	for p := s.Peek(); p != scanner.EOF && !strings.ContainsRune(stop, p); p = s.Peek() {
+		s.Next()
+	}
+}
@@ -36,49 +36,60 @@ type reg struct {
 	lns string
 }
 
-func (s *Parser) BodyValueStructured(k string) []string {
-	var str string
-	var rgx *regexp.Regexp
-
-	if val, ok := FieldPatterns[k]; ok {
-		str = regstrFromStructure(val["pattern"], val["fieldNames"])
-		rgx = regexp.MustCompile(str)
-	} else {
+// BodyValueStructured matches field k against its format and returns the
+// whole value followed by each of its components.
+func (m *Message) BodyValueStructured(k string) []string {
+	reg, err := m.registry()
+	if err != nil {
+		return []string{}
+	}
+	val, ok := reg.Lookup(m.messageType(), k)
+	if !ok {
+		return []string{}
+	}
+	f, err := formatFor(val["pattern"], val["fieldNames"])
+	if err != nil {
 		return []string{}
 	}
 
 	// Range over 4 and parse the fields
-	if val, ok := s.Map["4"][k]; ok {
-		return rgx.FindStringSubmatch(val.Val)
+	if fld, ok := m.Map["4"][k]; ok {
+		if caps, ok := f.Match(fld.Val); ok {
+			return append([]string{fld.Val}, caps...)
+		}
 	}
 
 	return []string{}
 }
 
-func (s *Parser) ParseBody() error {
-	var str string
-	var rgx *regexp.Regexp
-
-	if blk, ok := s.Map["4"]; ok {
-		for k, v := range blk {
-			if ptn, ok := FieldPatterns[k]; ok {
-				if fld, ok := s.Map["4"][k]; ok {
-					str = regstrFromStructure(ptn["pattern"], ptn["fieldNames"])
-					rgx = regexp.MustCompile(str)
-					mtc := rgx.FindStringSubmatch(v.Val)
-					nms := rgx.SubexpNames()
+// ParseBody decodes the components of each field in the text block into
+// Det, using the patterns of the message type from the application header
+// and the standards release selected for the message.
+func (m *Message) ParseBody() error {
+	reg, err := m.registry()
+	if err != nil {
+		return err
+	}
+	mt := m.messageType()
 
-					fld.Det = make(map[string]string)
-					if len(nms) == len(mtc) {
-						for i, name := range rgx.SubexpNames() {
-							if i != 0 {
-								fld.Det[name] = mtc[i]
-							}
-						}
-					}
+	// Map["4"] holds the last occurrence of each tag, which is decoded
+	// along with Body already.
+	last := map[string]Node{}
+	for i, fld := range m.Body {
+		if ptn, ok := reg.Lookup(mt, fld.Key); ok {
+			m.Body[i].Det = fieldDetail(ptn, fld.Val)
+			last[fld.Key] = m.Body[i]
+		}
+	}
 
-					s.Map["4"][k] = fld
-				}
+	if blk, ok := m.Map["4"]; ok {
+		for k, v := range blk {
+			if fld, ok := last[k]; ok && fld.Ind == v.Ind && fld.Val == v.Val {
+				v.Det = fld.Det
+				m.Map["4"][k] = v
+			} else if ptn, ok := reg.Lookup(mt, k); ok {
+				v.Det = fieldDetail(ptn, v.Val)
+				m.Map["4"][k] = v
 			}
 		}
 	}
@@ -86,12 +97,47 @@ func (s *Parser) ParseBody() error {
 	return nil
 }
 
+// Fields returns every occurrence of tag in the text block, in order. For
+// generic fields the tag may be followed by a qualifier, as in "98A:SETT".
+func (m *Message) Fields(tag string) []Node {
+	nds := []Node{}
+	for _, fld := range m.Body {
+		if fieldMatches(fld, tag) {
+			nds = append(nds, fld)
+		}
+	}
+	return nds
+}
+
+// Field returns the first occurrence of tag, which may carry a qualifier as
+// in "98A:SETT".
+func (m *Message) Field(tag string) (Node, bool) {
+	for _, fld := range m.Body {
+		if fieldMatches(fld, tag) {
+			return fld, true
+		}
+	}
+	return Node{}, false
+}
+
+func fieldDetail(ptn map[string]string, val string) map[string]string {
+	f, err := formatFor(ptn["pattern"], ptn["fieldNames"])
+	if err != nil {
+		return make(map[string]string)
+	}
+	return f.Detail(val)
+}
+
 func TextRegexCompilation() {
 	for k, v := range FieldPatterns {
 		p := v["pattern"]
 		rgx := regstrFromStructure(p, v["fieldNames"])
 		fmt.Println("Field - ", k, " SWIFT - ", p, " REGEX - ", rgx)
-		regexp.MustCompile(rgx)
+		if _, err := regexp.Compile(rgx); err != nil {
+			// Long formats such as 35*50x exceed the regexp repetition
+			// limit, fields are matched with ParseFormat instead.
+			fmt.Println("Field - ", k, " ERROR - ", err)
+		}
 	}
 }
 
@@ -101,8 +147,8 @@ func regstrFromStructure(str string, keys string) string {
 	var r reg
 
 	keys = strings.Replace(keys, "$", "", -1)
-	keys = regexp.MustCompile("^[(]|[)]$|[ -]").ReplaceAllString(keys, "")
-	kys := regexp.MustCompile("[)][(]").Split(keys, -1)
+	keys = nameTrim.ReplaceAllString(keys, "")
+	kys := nameSplit.Split(keys, -1)
 
 	mch := ""
 	rgx := ""
@@ -205,6 +251,19 @@ var FieldPatterns = map[string]map[string]string{
 		"pattern":    "12d",
 		"fieldNames": "(Rate)",
 	},
+	"61": {
+		// Use NewStatement to decode this field, the mark and funds code are ambiguous here
+		"pattern":    "6!n[4!n]2a[1!a]15d1!a3!c16x[//16x][$34x]",
+		"fieldNames": "(Value Date)(Entry Date)(Mark)(Funds Code)(Amount)(Transaction Type)(Identification Code)(Customer Reference)(Bank Reference)(Supplementary Details)",
+	},
+	"64": {
+		"pattern":    "1!a6!n3!a15d",
+		"fieldNames": "(Mark)(Date)(Currency)(Amount)",
+	},
+	"65": {
+		"pattern":    "1!a6!n3!a15d",
+		"fieldNames": "(Mark)(Date)(Currency)(Amount)",
+	},
 	// I think this field has structure, should define and alternative for this
 	"72": {
 		"pattern":    "6*35x",
@@ -219,9 +278,11 @@ var FieldPatterns = map[string]map[string]string{
 		"fieldNames": "(Narrative)",
 	},
 	"79": {
-		// Currently this isn't supported by go regex. Needs to be 1k max chars
-		// "pattern":    "35*50x",
-		"pattern":    "20*50x",
+		"pattern":    "35*50x",
+		"fieldNames": "(Narrative)",
+	},
+	"86": {
+		"pattern":    "6*65x",
 		"fieldNames": "(Narrative)",
 	},
 	"11A": {
@@ -256,6 +317,14 @@ var FieldPatterns = map[string]map[string]string{
 		"pattern":    ":4!c/[8c]/30x",
 		"fieldNames": "(Qualifier)(Data Source Scheme)(Number)",
 	},
+	"13C": {
+		"pattern":    "/8c/4!n1!x4!n",
+		"fieldNames": "(Code)(Time Indication)(Sign)(Time Offset)",
+	},
+	"13D": {
+		"pattern":    "6!n4!n1!x4!n",
+		"fieldNames": "(Date)(Time)(Sign)(Offset)",
+	},
 	"13J": {
 		"pattern":    ":4!c//5!c",
 		"fieldNames": "(Qualifier)(Extended Number Id)",
@@ -278,7 +347,7 @@ var FieldPatterns = map[string]map[string]string{
 	},
 	"19A": {
 		"pattern":    ":4!c//[N]3!a15d",
-		"fieldNames": "(Qualifier)(Sign)(Currency Code)(Amount)",
+		"fieldNames": "(Qualifier)(Currency Code)(Amount)",
 	},
 	"19B": {
 		"pattern":    ":4!c//3!a15d",
@@ -320,10 +389,22 @@ var FieldPatterns = map[string]map[string]string{
 		"pattern":    ":4!c/[8c]/4!c",
 		"fieldNames": "(Qualifier)(Data Source Scheme)(Status Code)",
 	},
+	"25P": {
+		"pattern":    "35x$4!a2!a2!c[3!c]",
+		"fieldNames": "(Account)$(Bank Code)(Country Code)(Location Code)(Branch Code)",
+	},
 	"26H": {
 		"pattern":    "16x",
 		"fieldNames": "",
 	},
+	"26T": {
+		"pattern":    "3!c",
+		"fieldNames": "(Type)",
+	},
+	"28C": {
+		"pattern":    "5n[/5n]",
+		"fieldNames": "(Statement Number)(Sequence Number)",
+	},
 	"28D": {
 		"pattern":    "5n/5n",
 		"fieldNames": "(Message Index)(Total)",
@@ -412,6 +493,10 @@ var FieldPatterns = map[string]map[string]string{
 		"pattern":    "3!a15d",
 		"fieldNames": "(Currency)(Amount)",
 	},
+	"34F": {
+		"pattern":    "3!a[1!a]15d",
+		"fieldNames": "(Currency)(Mark)(Amount)",
+	},
 	"35A": {
 		"pattern":    "3!a15d",
 		"fieldNames": "(Type)(Quantity)",
@@ -433,14 +518,12 @@ var FieldPatterns = map[string]map[string]string{
 		"fieldNames": "(Narrative)",
 	},
 	"35F": {
-		// Not supported by golangs regex lib
-		// "pattern":    "35*50x",
-		"pattern":    "20*50x",
+		"pattern":    "35*50x",
 		"fieldNames": "(Narrative)",
 	},
 	"35H": {
 		"pattern":    "[N]3!a15d",
-		"fieldNames": "(Sign)(Currency)(Quantity)",
+		"fieldNames": "(Currency)(Quantity)",
 	},
 	"35L": {
 		"pattern":    "4*35x",
@@ -468,7 +551,7 @@ var FieldPatterns = map[string]map[string]string{
 	},
 	"36E": {
 		"pattern":    ":4!c//4!c/[N]15d",
-		"fieldNames": "(Qualifier)(Quantity Type Code)(Sign)(Quantity)",
+		"fieldNames": "(Qualifier)(Quantity Type Code)(Quantity)",
 	},
 	"37A": {
 		"pattern":    "12d[//6!n1!a3n][/16x]",
@@ -498,6 +581,14 @@ var FieldPatterns = map[string]map[string]string{
 		"pattern":    "12d",
 		"fieldNames": "(Rate)",
 	},
+	"50A": {
+		"pattern":    "[/34x]$4!a2!a2!c[3!c]",
+		"fieldNames": "(Account)$(Bank Code)(Country Code)(Location Code)(Branch Code)",
+	},
+	"50F": {
+		"pattern":    "35x$4*35x",
+		"fieldNames": "(Party Identifier)$(Name and Address)",
+	},
 	"50H": {
 		"pattern":    "/34x$4*35x",
 		"fieldNames": "(Account)$(Name and Address)",
@@ -506,17 +597,25 @@ var FieldPatterns = map[string]map[string]string{
 		"pattern":    "[/34x]$4*35x",
 		"fieldNames": "(Account)$(Name and Address)",
 	},
+	"51A": {
+		"pattern":    "[/1!a][/34x]$4!a2!a2!c[3!c]",
+		"fieldNames": "(Mark)(Account)$(Bank Code)(Country Code)(Location Code)(Branch Code)",
+	},
 	"52A": {
 		"pattern":    "[/1!a][/34x]$4!a2!a2!c[3!c]",
-		"fieldNames": "(Party Identifier)$(Identifier Code)",
+		"fieldNames": "(Mark)(Account)$(Bank Code)(Country Code)(Location Code)(Branch Code)",
 	},
 	"52D": {
 		"pattern":    "[/1!a][/34x]$4*35x",
-		"fieldNames": "(Party Identifier)$(Name and Address)",
+		"fieldNames": "(Mark)(Account)$(Name and Address)",
 	},
 	"53A": {
 		"pattern":    "[/1!a][/34x]$4!a2!a2!c[3!c]",
-		"fieldNames": "(Party Identifier)$(Identifier Code)",
+		"fieldNames": "(Mark)(Account)$(Bank Code)(Country Code)(Location Code)(Branch Code)",
+	},
+	"53B": {
+		"pattern":    "[/1!a][/34x]$[35x]",
+		"fieldNames": "(Mark)(Account)$(Location)",
 	},
 	"53C": {
 		"pattern":    "/34x",
@@ -524,24 +623,97 @@ var FieldPatterns = map[string]map[string]string{
 	},
 	"53D": {
 		"pattern":    "[/1!a][/34x]$4*35x",
-		"fieldNames": "(Party Identifier)$(Name and Address)",
+		"fieldNames": "(Mark)(Account)$(Name and Address)",
+	},
+	"54A": {
+		"pattern":    "[/1!a][/34x]$4!a2!a2!c[3!c]",
+		"fieldNames": "(Mark)(Account)$(Bank Code)(Country Code)(Location Code)(Branch Code)",
+	},
+	"54B": {
+		"pattern":    "[/1!a][/34x]$[35x]",
+		"fieldNames": "(Mark)(Account)$(Location)",
+	},
+	"54D": {
+		"pattern":    "[/1!a][/34x]$4*35x",
+		"fieldNames": "(Mark)(Account)$(Name and Address)",
+	},
+	"55A": {
+		"pattern":    "[/1!a][/34x]$4!a2!a2!c[3!c]",
+		"fieldNames": "(Mark)(Account)$(Bank Code)(Country Code)(Location Code)(Branch Code)",
+	},
+	"55B": {
+		"pattern":    "[/1!a][/34x]$[35x]",
+		"fieldNames": "(Mark)(Account)$(Location)",
+	},
+	"55D": {
+		"pattern":    "[/1!a][/34x]$4*35x",
+		"fieldNames": "(Mark)(Account)$(Name and Address)",
+	},
+	"56A": {
+		"pattern":    "[/1!a][/34x]$4!a2!a2!c[3!c]",
+		"fieldNames": "(Mark)(Account)$(Bank Code)(Country Code)(Location Code)(Branch Code)",
+	},
+	"56C": {
+		"pattern":    "/34x",
+		"fieldNames": "(Account)",
+	},
+	"56D": {
+		"pattern":    "[/1!a][/34x]$4*35x",
+		"fieldNames": "(Mark)(Account)$(Name and Address)",
 	},
 	"57A": {
 		"pattern":    "[/1!a][/34x]$4!a2!a2!c[3!c]",
-		"fieldNames": "(Party Identifier)$(Identifier Code)",
+		"fieldNames": "(Mark)(Account)$(Bank Code)(Country Code)(Location Code)(Branch Code)",
 	},
 	"57B": {
 		"pattern":    "[/1!a][/34x]$[35x]",
-		"fieldNames": "(Party Identifier)$(Location)",
+		"fieldNames": "(Mark)(Account)$(Location)",
+	},
+	"57C": {
+		"pattern":    "/34x",
+		"fieldNames": "(Account)",
 	},
 	"57D": {
 		"pattern":    "[/1!a][/34x]$4*35x",
-		"fieldNames": "(Party Identifier)$(Name and Address)",
+		"fieldNames": "(Mark)(Account)$(Name and Address)",
+	},
+	"58A": {
+		"pattern":    "[/1!a][/34x]$4!a2!a2!c[3!c]",
+		"fieldNames": "(Mark)(Account)$(Bank Code)(Country Code)(Location Code)(Branch Code)",
+	},
+	"58D": {
+		"pattern":    "[/1!a][/34x]$4*35x",
+		"fieldNames": "(Mark)(Account)$(Name and Address)",
 	},
 	"59": {
 		"pattern":    "[/34x]$4*35x",
 		"fieldNames": "(Account)$(Name and Address)",
 	},
+	"59A": {
+		"pattern":    "[/34x]$4!a2!a2!c[3!c]",
+		"fieldNames": "(Account)$(Bank Code)(Country Code)(Location Code)(Branch Code)",
+	},
+	"59F": {
+		// The name and address lines are structured as 4*(1!n/33x)
+		"pattern":    "[/34x]$4*35x",
+		"fieldNames": "(Account)$(Name and Address)",
+	},
+	"60F": {
+		"pattern":    "1!a6!n3!a15d",
+		"fieldNames": "(Mark)(Date)(Currency)(Amount)",
+	},
+	"60M": {
+		"pattern":    "1!a6!n3!a15d",
+		"fieldNames": "(Mark)(Date)(Currency)(Amount)",
+	},
+	"62F": {
+		"pattern":    "1!a6!n3!a15d",
+		"fieldNames": "(Mark)(Date)(Currency)(Amount)",
+	},
+	"62M": {
+		"pattern":    "1!a6!n3!a15d",
+		"fieldNames": "(Mark)(Date)(Currency)(Amount)",
+	},
 	"67A": {
 		"pattern":    "6!n[/6!n]",
 		"fieldNames": "(Date 1)(Date 2)",
@@ -591,9 +763,7 @@ var FieldPatterns = map[string]map[string]string{
 		"fieldNames": "(Qualifier)(Narrative)",
 	},
 	"70F": {
-		// Currently this is not supported by golang regex. Need to work out a better implementation
-		// "pattern":    ":4!c//8000z",
-		"pattern":    ":4!c//800z",
+		"pattern":    ":4!c//8000z",
 		"fieldNames": "(Qualifier)(Narrative)",
 	},
 	"70G": {
@@ -616,10 +786,18 @@ var FieldPatterns = map[string]map[string]string{
 		"pattern":    "3!a15d",
 		"fieldNames": "(Code)(Amount)",
 	},
+	"71G": {
+		"pattern":    "3!a15d",
+		"fieldNames": "(Currency)(Amount)",
+	},
 	"77A": {
 		"pattern":    "20*35x",
 		"fieldNames": "(Narrative)",
 	},
+	"77B": {
+		"pattern":    "3*35x",
+		"fieldNames": "(Narrative)",
+	},
 	"77D": {
 		"pattern":    "6*35x",
 		"fieldNames": "(Narrative)",
@@ -634,7 +812,7 @@ var FieldPatterns = map[string]map[string]string{
 	},
 	"83A": {
 		"pattern":    "[/1!a][/34x]$4!a2!a2!c[3!c]",
-		"fieldNames": "(Party Identifier)$(Identifier Code)",
+		"fieldNames": "(Mark)(Account)$(Bank Code)(Country Code)(Location Code)(Branch Code)",
 	},
 	"83C": {
 		"pattern":    "/34x",
@@ -642,15 +820,15 @@ var FieldPatterns = map[string]map[string]string{
 	},
 	"83D": {
 		"pattern":    "[/1!a][/34x]$4*35x",
-		"fieldNames": "(Party Identifier)$(Name and Address)",
+		"fieldNames": "(Mark)(Account)$(Name and Address)",
 	},
 	"87A": {
 		"pattern":    "[/1!a][/34x]$4!a2!a2!c[3!c]",
-		"fieldNames": "(Party Identifier)$(Identifier Code)",
+		"fieldNames": "(Mark)(Account)$(Bank Code)(Country Code)(Location Code)(Branch Code)",
 	},
 	"87D": {
 		"pattern":    "[/1!a][/34x]$4*35x",
-		"fieldNames": "(Party Identifier)$(Name and Address)",
+		"fieldNames": "(Mark)(Account)$(Name and Address)",
 	},
 	"90A": {
 		"pattern":    ":4!c//4!c/15d",
@@ -660,6 +838,14 @@ var FieldPatterns = map[string]map[string]string{
 		"pattern":    ":4!c//4!c/3!a15d",
 		"fieldNames": "(Qualifier)(Amount Type Code)(Currency Code)(Price)",
 	},
+	"90C": {
+		"pattern":    "5n3!a15d",
+		"fieldNames": "(Number)(Currency)(Amount)",
+	},
+	"90D": {
+		"pattern":    "5n3!a15d",
+		"fieldNames": "(Number)(Currency)(Amount)",
+	},
 	"90E": {
 		"pattern":    ":4!c//4!c",
 		"fieldNames": "(Qualifier)(Price Code)",
@@ -678,11 +864,11 @@ var FieldPatterns = map[string]map[string]string{
 	},
 	"90L": {
 		"pattern":    ":4!c//[N]15d",
-		"fieldNames": "(Qualifier)(Sign)(Index Points)",
+		"fieldNames": "(Qualifier)(Index Points)",
 	},
 	"92A": {
 		"pattern":    ":4!c//[N]15d",
-		"fieldNames": "(Qualifier)(Sign)(Rate)",
+		"fieldNames": "(Qualifier)(Rate)",
 	},
 	"92B": {
 		"pattern":    ":4!c//3!a/3!a/15d",
@@ -734,15 +920,15 @@ var FieldPatterns = map[string]map[string]string{
 	},
 	"93B": {
 		"pattern":    ":4!c/[8c]/4!c/[N]15d",
-		"fieldNames": "(Qualifier)(Data Source Scheme)(Quantity Type Code)(Sign)(Balance)",
+		"fieldNames": "(Qualifier)(Data Source Scheme)(Quantity Type Code)(Balance)",
 	},
 	"93C": {
 		"pattern":    ":4!c//4!c/4!c/[N]15d",
-		"fieldNames": "(Qualifier)(Quantity Type Code)(Balance Type Code)(Sign)(Balance)",
+		"fieldNames": "(Qualifier)(Quantity Type Code)(Balance Type Code)(Balance)",
 	},
 	"93D": {
 		"pattern":    ":4!c//[N]15d",
-		"fieldNames": "(Qualifier)(Sign)(Balance)",
+		"fieldNames": "(Qualifier)(Balance)",
 	},
 	"94B": {
 		"pattern":    ":4!c/[8c]/4!c[/30x]",
@@ -762,7 +948,7 @@ var FieldPatterns = map[string]map[string]string{
 	},
 	"94F": {
 		"pattern":    ":4!c//4!c/4!a2!a2!c[3!c]",
-		"fieldNames": "(Qualifier)(Place Code)(Identifier Code)",
+		"fieldNames": "(Qualifier)(Place Code)(Bank Code)(Country Code)(Location Code)(Branch Code)",
 	},
 	"94G": {
 		"pattern":    ":4!c//2*35x",
@@ -770,7 +956,7 @@ var FieldPatterns = map[string]map[string]string{
 	},
 	"94H": {
 		"pattern":    ":4!c//4!a2!a2!c[3!c]",
-		"fieldNames": "(Qualifier)(Identifier Code)",
+		"fieldNames": "(Qualifier)(Bank Code)(Country Code)(Location Code)(Branch Code)",
 	},
 	"95C": {
 		"pattern":    ":4!c//2!a",
@@ -778,7 +964,7 @@ var FieldPatterns = map[string]map[string]string{
 	},
 	"95P": {
 		"pattern":    ":4!c//4!a2!a2!c[3!c]",
-		"fieldNames": "(Qualifier)(Identifier Code)",
+		"fieldNames": "(Qualifier)(Bank Code)(Country Code)(Location Code)(Branch Code)",
 	},
 	"95Q": {
 		"pattern":    ":4!c//4*35x",
@@ -842,7 +1028,7 @@ var FieldPatterns = map[string]map[string]string{
 	},
 	"99A": {
 		"pattern":    ":4!c//[N]3!n",
-		"fieldNames": "(Qualifier)(Sign)(Number)",
+		"fieldNames": "(Qualifier)(Number)",
 	},
 	"99B": {
 		"pattern":    ":4!c//3!n",
@@ -0,0 +1,83 @@
+package mtparser
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+const readmeMT103 = `{1:F01AAAAGRA0AXXX0057000289}{2:O1030919010321BBBBGRA0AXXX00570001710103210920N}{4:
+:20:5387354
+:23B:CRED
+:23E:PHOB/20.527.19.60
+:32A:000526USD1101,50
+:33B:USD1121,50
+:50K:FRANZ HOLZAPFEL GMBH
+VIENNA
+:52A:BKAUATWW
+:59:723491524
+C. KLEIN
+BLOEMENGRACHT 15
+AMSTERDAM
+:71A:SHA
+:71F:USD10,
+:71F:USD10,
+:72:/INS/CHASUS33
+-}{5:{MAC:75D138E4}{CHK:DE1B0D71FA96}}`
+
+func parseMessage(tb testing.TB, msg string) *Message {
+	tb.Helper()
+	psr, err := New(bufio.NewReader(strings.NewReader(msg)))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err := psr.Parse(); err != nil {
+		tb.Fatal(err)
+	}
+	return &psr.Message
+}
+
+func cachedFormats() int {
+	n := 0
+	formats.Range(func(any, any) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// TestParseBodyAllocs checks that a warm ParseBody parses no formats. Formats
+// are only parsed when formatFor misses the cache, which adds an entry.
+func TestParseBodyAllocs(t *testing.T) {
+	msg := parseMessage(t, readmeMT103)
+	msg.ParseBody()
+
+	if det := msg.Map["4"]["32A"].Det; det["Currency"] != "USD" || det["Amount"] != "1101,50" {
+		t.Fatalf("32A detail = %v", det)
+	}
+
+	n := cachedFormats()
+	warm := testing.AllocsPerRun(100, func() {
+		msg.ParseBody()
+	})
+	if m := cachedFormats(); m != n {
+		t.Errorf("ParseBody parsed %d formats after warm-up", m-n)
+	}
+
+	cold := testing.AllocsPerRun(10, func() {
+		formats.Clear()
+		msg.ParseBody()
+	})
+	if warm >= cold {
+		t.Errorf("ParseBody allocates %.0f times with cached formats and %.0f times without", warm, cold)
+	}
+}
+
+func BenchmarkParseBody(b *testing.B) {
+	msg := parseMessage(b, readmeMT103)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		msg.ParseBody()
+	}
+}
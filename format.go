@@ -0,0 +1,421 @@
+package mtparser
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	upperChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	lowerChars = "abcdefghijklmnopqrstuvwxyz"
+	digitChars = "0123456789"
+)
+
+// swiftCharsets holds the characters allowed by each SWIFT character set.
+// Only z allows line breaks within a single component.
+var swiftCharsets = map[byte]*[256]bool{
+	'n': charset(digitChars),
+	'd': charset(digitChars + ","),
+	'h': charset(digitChars + "ABCDEF"),
+	'a': charset(upperChars),
+	'c': charset(digitChars + upperChars),
+	'e': charset(" "),
+	'x': charset(upperChars + lowerChars + digitChars + "/-?:().,'+ "),
+	'y': charset(upperChars + digitChars + ".,-()/='+:?!\"%&*<>; "),
+	'z': charset(upperChars + lowerChars + digitChars + ".,-()/='+:?@#_{!\"%&*;<> \n"),
+}
+
+var (
+	nameTrim  = regexp.MustCompile("^[(]|[)]$|[ -]")
+	nameSplit = regexp.MustCompile("[)][(]")
+)
+
+// formats caches parsed formats by spec and names so that decoding a field
+// does not parse its format again.
+var formats sync.Map
+
+type cachedFormat struct {
+	f   *Format
+	err error
+}
+
+// literalChars holds the characters a format may use outside components.
+var literalChars = charset(upperChars + "/:,-+.()?' ")
+
+func charset(chars string) *[256]bool {
+	var set [256]bool
+	for i := 0; i < len(chars); i++ {
+		set[chars[i]] = true
+	}
+	return &set
+}
+
+// ItemKind is the kind of an element of a format specification.
+type ItemKind int
+
+const (
+	// Literal is fixed text such as the "/" in "[/34x]".
+	Literal ItemKind = iota
+	// Component is a run of characters from one charset such as "3!a".
+	Component
+	// Optional is a "[...]" group holding further items.
+	Optional
+	// Newline is a "$" line separator.
+	Newline
+)
+
+// Item is one element of a parsed format specification.
+type Item struct {
+	Kind ItemKind
+	// Text of a Literal.
+	Text string
+	// Name of a Component, as used for the keys of Node.Det.
+	Name string
+	// Charset of a Component, one of n, d, h, a, c, e, x, y and z.
+	Charset byte
+	// Length is the exact length when Fixed is set and the maximum
+	// length otherwise. For multi-line components it is per line.
+	Length int
+	Fixed  bool
+	// Lines is the maximum number of lines as in "4*35x", 0 for single
+	// line components and -1 for an unbounded number as in "n*78x".
+	Lines int
+	// Optional is set for components inside a "[...]" group.
+	Optional bool
+	// Index of a Component among all components of the format.
+	Index int
+	// Items of an Optional group.
+	Items []Item
+}
+
+// String returns the item in SWIFT format notation.
+func (it *Item) String() string {
+	switch it.Kind {
+	case Literal:
+		return it.Text
+	case Newline:
+		return "$"
+	case Optional:
+		return "[" + itemsString(it.Items) + "]"
+	}
+
+	s := ""
+	switch {
+	case it.Lines < 0:
+		s = "n*"
+	case it.Lines > 0:
+		s = strconv.Itoa(it.Lines) + "*"
+	}
+	s += strconv.Itoa(it.Length)
+	if it.Fixed {
+		s += "!"
+	}
+	return s + string(it.Charset)
+}
+
+func itemsString(items []Item) string {
+	s := ""
+	for i := range items {
+		s += items[i].String()
+	}
+	return s
+}
+
+// Format is a parsed SWIFT format specification such as "3!a15d" or
+// "[/1!a][/34x]$4*35x".
+type Format struct {
+	Spec  string
+	Items []Item
+	comps []*Item
+}
+
+// ParseFormat parses a format specification and names its components in
+// order from a list such as "(Account)$(Name and Address)". Spaces and
+// dashes are removed from the names and components without a name are left
+// unnamed.
+func ParseFormat(spec string, names string) (*Format, error) {
+	f := &Format{Spec: spec}
+
+	pos := 0
+	items, err := f.parseItems(&pos, 0)
+	if err != nil {
+		return nil, err
+	}
+	f.Items = items
+
+	kys := formatNames(names)
+	walkComponents(f.Items, false, func(it *Item) {
+		if len(kys) > 0 {
+			it.Name = kys[0]
+			kys = kys[1:]
+		}
+		it.Index = len(f.comps)
+		f.comps = append(f.comps, it)
+	})
+
+	return f, nil
+}
+
+// formatNames splits a field name list such as "(Account)$(Name and Address)"
+// into the names used as detail keys.
+func formatNames(names string) []string {
+	names = strings.Replace(names, "$", "", -1)
+	names = nameTrim.ReplaceAllString(names, "")
+	return nameSplit.Split(names, -1)
+}
+
+// formatFor returns the parsed format for spec and names, parsing it on first
+// use. The returned Format is shared and must not be modified.
+func formatFor(spec string, names string) (*Format, error) {
+	key := spec + "\x00" + names
+	if c, ok := formats.Load(key); ok {
+		return c.(cachedFormat).f, c.(cachedFormat).err
+	}
+	f, err := ParseFormat(spec, names)
+	c, _ := formats.LoadOrStore(key, cachedFormat{f, err})
+	return c.(cachedFormat).f, c.(cachedFormat).err
+}
+
+// Components returns the components of the format in order, including those
+// inside optional groups.
+func (f *Format) Components() []*Item {
+	return f.comps
+}
+
+// String returns the format in SWIFT format notation.
+func (f *Format) String() string {
+	return itemsString(f.Items)
+}
+
+func (f *Format) parseItems(pos *int, depth int) ([]Item, error) {
+	var items []Item
+	spec := f.Spec
+
+	for *pos < len(spec) {
+		c := spec[*pos]
+
+		switch {
+		case c == '[':
+			*pos++
+			sub, err := f.parseItems(pos, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			if *pos >= len(spec) || spec[*pos] != ']' {
+				return nil, errors.New("Unclosed '[' in format " + spec)
+			}
+			*pos++
+			items = append(items, Item{Kind: Optional, Items: sub})
+		case c == ']':
+			if depth == 0 {
+				return nil, errors.New("Unexpected ']' in format " + spec)
+			}
+			return items, nil
+		case c == '$':
+			*pos++
+			items = append(items, Item{Kind: Newline})
+		case c >= '0' && c <= '9', c == 'n' && *pos+1 < len(spec) && spec[*pos+1] == '*':
+			it, err := f.parseComponent(pos)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, it)
+		case !literalChars[c]:
+			return nil, errors.New("Unexpected '" + string(c) + "' at position " + strconv.Itoa(*pos) + " of format " + spec)
+		default:
+			*pos++
+			if l := len(items) - 1; l >= 0 && items[l].Kind == Literal {
+				items[l].Text += string(c)
+			} else {
+				items = append(items, Item{Kind: Literal, Text: string(c)})
+			}
+		}
+	}
+
+	if depth > 0 {
+		return nil, errors.New("Unclosed '[' in format " + f.Spec)
+	}
+	return items, nil
+}
+
+// parseComponent parses a component such as "35x", "3!a", "4*35x" or "n*78x".
+func (f *Format) parseComponent(pos *int) (Item, error) {
+	it := Item{Kind: Component}
+	spec := f.Spec
+
+	num := func() int {
+		s := *pos
+		for *pos < len(spec) && spec[*pos] >= '0' && spec[*pos] <= '9' {
+			*pos++
+		}
+		n, _ := strconv.Atoi(spec[s:*pos])
+		return n
+	}
+
+	if spec[*pos] == 'n' {
+		*pos += 2
+		it.Lines = -1
+	} else {
+		it.Length = num()
+		if *pos < len(spec) && spec[*pos] == '*' {
+			*pos++
+			it.Lines = it.Length
+		}
+	}
+	if it.Lines != 0 {
+		it.Length = num()
+	}
+
+	if *pos < len(spec) && spec[*pos] == '!' {
+		*pos++
+		it.Fixed = true
+	}
+	if *pos >= len(spec) || swiftCharsets[spec[*pos]] == nil || it.Length == 0 {
+		return it, errors.New("Invalid component at position " + strconv.Itoa(*pos) + " of format " + spec)
+	}
+	it.Charset = spec[*pos]
+	*pos++
+
+	return it, nil
+}
+
+func walkComponents(items []Item, opt bool, fn func(it *Item)) {
+	for i := range items {
+		switch items[i].Kind {
+		case Component:
+			items[i].Optional = opt
+			fn(&items[i])
+		case Optional:
+			walkComponents(items[i].Items, true, fn)
+		}
+	}
+}
+
+// Match matches the whole of val and returns the value of each component,
+// empty for optional components that are absent.
+func (f *Format) Match(val string) ([]string, bool) {
+	caps := make([]string, len(f.comps))
+	ok := matchItems(f.Items, val, 0, caps, func(pos int) bool {
+		return pos == len(val)
+	})
+	return caps, ok
+}
+
+// Detail returns the named components of val, or an empty map when val does
+// not match the format.
+func (f *Format) Detail(val string) map[string]string {
+	det := make(map[string]string)
+	caps, ok := f.Match(val)
+	if !ok {
+		return det
+	}
+	for _, it := range f.comps {
+		if len(it.Name) > 0 {
+			det[it.Name] = caps[it.Index]
+		}
+	}
+	return det
+}
+
+func matchItems(items []Item, val string, pos int, caps []string, k func(int) bool) bool {
+	if len(items) == 0 {
+		return k(pos)
+	}
+
+	it := &items[0]
+	next := func(p int) bool {
+		return matchItems(items[1:], val, p, caps, k)
+	}
+
+	switch it.Kind {
+	case Literal:
+		return strings.HasPrefix(val[pos:], it.Text) && next(pos+len(it.Text))
+	case Newline:
+		if strings.HasPrefix(val[pos:], "\n") && next(pos+1) {
+			return true
+		}
+		// The line break of an absent optional line is absent as well.
+		return (pos == 0 || pos == len(val) || val[pos-1] == '\n') && next(pos)
+	case Optional:
+		return matchItems(it.Items, val, pos, caps, next) || next(pos)
+	}
+
+	for _, end := range it.ends(val, pos) {
+		caps[it.Index] = val[pos:end]
+		if next(end) {
+			return true
+		}
+	}
+	caps[it.Index] = ""
+	return false
+}
+
+// ends lists the positions a component starting at pos may end at, longest
+// first.
+func (it *Item) ends(val string, pos int) []int {
+	set := swiftCharsets[it.Charset]
+	run := func(p int) int {
+		n := 0
+		for p+n < len(val) && n < it.Length && set[val[p+n]] {
+			n++
+		}
+		return n
+	}
+
+	var ends []int
+
+	if it.Lines == 0 {
+		n := run(pos)
+		if it.Fixed {
+			if n == it.Length {
+				ends = append(ends, pos+n)
+			}
+			return ends
+		}
+		for ; n > 0; n-- {
+			ends = append(ends, pos+n)
+		}
+		return ends
+	}
+
+	// Every line but the last is taken whole, so the candidates are the
+	// ends of the last line for each possible number of lines.
+	var lns [][2]int
+	for p := pos; it.Lines < 0 || len(lns) < it.Lines; {
+		n := run(p)
+		if n == 0 {
+			break
+		}
+		lns = append(lns, [2]int{p, n})
+		if p+n >= len(val) || val[p+n] != '\n' {
+			break
+		}
+		p += n + 1
+	}
+	for i := len(lns) - 1; i >= 0; i-- {
+		for n := lns[i][1]; n > 0; n-- {
+			ends = append(ends, lns[i][0]+n)
+		}
+	}
+	return ends
+}
+
+// ValidateField checks a field value against the format of its tag in
+// DefaultRegistry. Tags without a format are not checked.
+func ValidateField(tag string, val string) error {
+	ptn, ok := DefaultRegistry.Lookup("", tag)
+	if !ok {
+		return nil
+	}
+	f, err := formatFor(ptn["pattern"], ptn["fieldNames"])
+	if err != nil {
+		return err
+	}
+	if _, ok := f.Match(val); !ok {
+		return errors.New("Field " + tag + " does not match format " + ptn["pattern"])
+	}
+	return nil
+}
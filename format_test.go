@@ -0,0 +1,91 @@
+package mtparser
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func lines(n int, ln string) string {
+	return strings.TrimSuffix(strings.Repeat(ln+"\n", n), "\n")
+}
+
+func TestFormatMatch(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		val  string
+		ok   bool
+		caps []string
+	}{
+		{"account and name", "[/34x]$4*35x", "/12345\nJOHN DOE\nSTREET", true, []string{"12345", "JOHN DOE\nSTREET"}},
+		{"name without account", "[/34x]$4*35x", "JOHN DOE\nSTREET", true, []string{"", "JOHN DOE\nSTREET"}},
+		{"slash in name", "[/34x]$4*35x", "/12345", true, []string{"", "/12345"}},
+		{"five name lines", "[/34x]$4*35x", "/1\n" + lines(5, "A"), false, nil},
+		{"line of 35", "4*35x", lines(4, strings.Repeat("A", 35)), true, []string{lines(4, strings.Repeat("A", 35))}},
+		{"line of 36", "4*35x", "A\n" + strings.Repeat("A", 36), false, nil},
+		{"35 lines", "35*50x", lines(35, strings.Repeat("B", 50)), true, []string{lines(35, strings.Repeat("B", 50))}},
+		{"36 lines", "35*50x", lines(36, "B"), false, nil},
+		{"empty line", "4*35x", "A\n\nB", false, nil},
+		{"8000z", ":4!c//8000z", ":ADTX//" + strings.Repeat("a b\n", 2000), true, []string{"ADTX", strings.Repeat("a b\n", 2000)}},
+		{"8001z", ":4!c//8000z", ":ADTX//" + strings.Repeat("a b\n", 2000) + "c", false, nil},
+		{"fixed length", "3!a", "EUR", true, []string{"EUR"}},
+		{"fixed too short", "3!a", "EU", false, nil},
+		{"fixed too long", "3!a", "EURO", false, nil},
+		{"max length", "16x", strings.Repeat("R", 16), true, []string{strings.Repeat("R", 16)}},
+		{"max too long", "16x", strings.Repeat("R", 17), false, nil},
+		{"max at least one", "16x", "", false, nil},
+		{"charset", "3!a15d", "EUR1000,50", true, []string{"EUR", "1000,50"}},
+		{"wrong charset", "3!a15d", "eur1000,50", false, nil},
+		{"backtrack", "6!n[4!n]2a", "2401020102CD", true, []string{"240102", "0102", "CD"}},
+		{"backtrack without optional", "6!n[4!n]2a", "240102CD", true, []string{"240102", "", "CD"}},
+		{"unbounded lines", "73x$[n*78x]", "HEAD\n" + lines(100, "L"), true, []string{"HEAD", lines(100, "L")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParseFormat(tt.spec, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			caps, ok := f.Match(tt.val)
+			if ok != tt.ok {
+				t.Fatalf("Match(%q) = %v, want %v", tt.spec, ok, tt.ok)
+			}
+			if ok && !slices.Equal(caps, tt.caps) {
+				t.Errorf("Match(%q) = %q, want %q", tt.spec, caps, tt.caps)
+			}
+		})
+	}
+}
+
+func TestFormatDetail(t *testing.T) {
+	f, err := ParseFormat("[/34x]$4*35x", "(Account)$(Name and Address)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	det := f.Detail("JOHN DOE")
+	if det["Account"] != "" || det["NameandAddress"] != "JOHN DOE" {
+		t.Errorf("Detail = %v", det)
+	}
+	if det := f.Detail(strings.Repeat("A", 36)); len(det) != 0 {
+		t.Errorf("Detail of a value that does not match = %v, want empty", det)
+	}
+}
+
+func TestParseFormatErrors(t *testing.T) {
+	for _, spec := range []string{"3a!", "16x*", "4!c//*35x", "x", "16", "[3!a", "3!a]", "4*x"} {
+		if _, err := ParseFormat(spec, ""); err == nil {
+			t.Errorf("ParseFormat(%q) succeeded, want an error", spec)
+		}
+	}
+	for k, ptn := range FieldPatterns {
+		f, err := ParseFormat(ptn["pattern"], ptn["fieldNames"])
+		if err != nil {
+			t.Errorf("field %s: %v", k, err)
+		} else if f.String() != ptn["pattern"] {
+			t.Errorf("field %s: String() = %q, want %q", k, f.String(), ptn["pattern"])
+		}
+	}
+}
@@ -2,7 +2,6 @@ package mtparser
 
 import (
 	"bytes"
-	"errors"
 	"regexp"
 	"text/scanner"
 )
@@ -20,22 +19,31 @@ func (s *Parser) scanHeader() (err error) {
 	b := bytes.NewBufferString("")
 
 	s.Mode = scanner.ScanChars
+	defer func() { s.Mode = scanner.ScanIdents }()
+
+	if s.Peek() == '}' {
+		return s.tolerate(s.errUnexpected('}'))
+	}
 
 	c = s.Scan()
 	switch c {
 	case 'I':
-		max = 17
+		max = 21
 	case 'O':
 		max = 47
 	default:
 		max = 25
 	}
 
+scan:
 	for i := 1; i <= max; i++ {
 		switch c {
 		case '{', '}', ':', '-', '/':
-			err = errors.New(s.ErrMessage(c, false))
-			return
+			if err = s.tolerate(s.errUnexpected(c)); err != nil {
+				return
+			}
+			s.skipTo("}")
+			break scan
 		default:
 			b.WriteRune(c)
 		}
@@ -68,10 +76,27 @@ func (s *Parser) scanHeader() (err error) {
 		if i == max {
 			break
 		}
+		if s.Peek() == '}' {
+			// Priority, monitoring and obsolescence are optional in the
+			// input header and checked in checkInputHeader.
+			if max == 21 && i >= 16 {
+				break
+			}
+			if err = s.tolerate(s.errUnexpected('}')); err != nil {
+				return
+			}
+			break
+		}
 
 		c = s.Scan()
 	}
 
+	if max == 21 {
+		if err = s.checkInputHeader(mp, b.String()); err != nil {
+			return
+		}
+	}
+
 	if v, ok := mp["source"]; ok {
 		if bic := bicSplit.FindStringSubmatch(v.Val); bic != nil {
 			v.Det = map[string]string{
@@ -94,7 +119,44 @@ func (s *Parser) scanHeader() (err error) {
 	}
 
 	s.Map[s.blk.Key] = mp
-	s.Mode = scanner.ScanIdents
+	return nil
+}
+
+var inputChecks = []struct {
+	key string
+	rgx *regexp.Regexp
+}{
+	{"type", regexp.MustCompile("^[0-9]{3}$")},
+	{"destination", regexp.MustCompile("^[0-9A-Z]{12}$")},
+	{"priority", regexp.MustCompile("^[SUN]$")},
+	{"monitoring", regexp.MustCompile("^[123]$")},
+	{"obsolescence", regexp.MustCompile("^[0-9]{3}$")},
+}
+
+// checkInputHeader validates the fields of an input application header.
+// rest holds any trailing characters that did not make up a whole field.
+func (s *Parser) checkInputHeader(mp map[string]Node, rest string) error {
+	if len(rest) > 0 {
+		if err := s.tolerate(s.errInvalid("obsolescence", rest)); err != nil {
+			return err
+		}
+	}
+
+	for _, chk := range inputChecks {
+		if v, ok := mp[chk.key]; ok && !chk.rgx.MatchString(v.Val) {
+			if err := s.tolerate(s.errInvalid(chk.key, v.Val)); err != nil {
+				return err
+			}
+		}
+	}
+
+	// A non-delivery warning can only be requested for urgent messages.
+	if v, ok := mp["monitoring"]; ok && v.Val != "2" && mp["priority"].Val != "U" {
+		if err := s.tolerate(s.errInvalid("monitoring", v.Val)); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,132 @@
+package mtparser
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// InfoDialect decodes one structure of field 86 into key/value details. Decode
+// reports false when the text is not in the dialect.
+type InfoDialect interface {
+	Name() string
+	Decode(txt string) (map[string]string, bool)
+}
+
+// Info is a decoded field 86. Dialect is empty when no dialect recognised the
+// text, in which case Details is empty.
+type Info struct {
+	Dialect string
+	Details map[string]string
+	Text    string
+}
+
+var (
+	dialectMu     sync.RWMutex
+	infoDialects  []InfoDialect
+	builtDialects = []InfoDialect{dfueDialect{}, slashDialect{name: "nl", marker: "TRTP"}, slashDialect{name: "sepa"}}
+)
+
+// RegisterInfoDialect adds a dialect. Registered dialects are tried in the
+// reverse order of registration and before the built-in ones.
+func RegisterInfoDialect(d InfoDialect) {
+	dialectMu.Lock()
+	defer dialectMu.Unlock()
+	infoDialects = append([]InfoDialect{d}, infoDialects...)
+}
+
+// DecodeInfo decodes field 86 with the first dialect that recognises it.
+func DecodeInfo(txt string) *Info {
+	dialectMu.RLock()
+	dls := append(append([]InfoDialect{}, infoDialects...), builtDialects...)
+	dialectMu.RUnlock()
+
+	for _, d := range dls {
+		if det, ok := d.Decode(txt); ok {
+			return &Info{Dialect: d.Name(), Details: det, Text: txt}
+		}
+	}
+	return &Info{Details: map[string]string{}, Text: txt}
+}
+
+// Details decodes the field 86 that followed the statement line.
+func (l *StatementLine) Details() *Info {
+	return DecodeInfo(l.Information)
+}
+
+var (
+	dfueStart = regexp.MustCompile(`^([0-9]{3})\?`)
+	dfueSplit = regexp.MustCompile(`\?([0-9]{2})`)
+)
+
+// dfueDialect decodes the German DFÜ structure of a business transaction
+// code followed by ?nn subfields.
+type dfueDialect struct{}
+
+func (dfueDialect) Name() string {
+	return "dfue"
+}
+
+func (dfueDialect) Decode(txt string) (map[string]string, bool) {
+	txt = strings.ReplaceAll(txt, "\n", "")
+	mtc := dfueStart.FindStringSubmatch(txt)
+	if mtc == nil {
+		return nil, false
+	}
+
+	det := map[string]string{"GVC": mtc[1]}
+	idx := dfueSplit.FindAllStringSubmatchIndex(txt, -1)
+	for i, m := range idx {
+		end := len(txt)
+		if i+1 < len(idx) {
+			end = idx[i+1][0]
+		}
+		det[txt[m[2]:m[3]]] = txt[m[1]:end]
+	}
+
+	det["PostingText"] = det["00"]
+	det["BankCode"] = det["30"]
+	det["Account"] = det["31"]
+	det["Name"] = det["32"] + det["33"]
+	for _, k := range []string{"20", "21", "22", "23", "24", "25", "26", "27", "28", "29", "60", "61", "62", "63"} {
+		det["Purpose"] += det[k]
+	}
+
+	return det, true
+}
+
+var slashSplit = regexp.MustCompile(`/(TRTP|IBAN|BIC|NAME|REMI|EREF|MARF|CSID|ORDP|BENM|ID|ADDR|CNTP|PREF|RTRN|ISDT|USTD|STRD|CDTRREFTP|CDTRREF|CD|ISSR|PURP|ULTC|ULTD|EXCH|CHGS|OCMT|SVCL)/`)
+
+// slashDialect decodes /KEY/value structures such as the Dutch /TRTP/ codes
+// and SEPA style /NAME/ and /IBAN/ keys. When marker is set the text must
+// start with that key.
+type slashDialect struct {
+	name   string
+	marker string
+}
+
+func (d slashDialect) Name() string {
+	return d.name
+}
+
+func (d slashDialect) Decode(txt string) (map[string]string, bool) {
+	txt = strings.ReplaceAll(txt, "\n", "")
+	if len(d.marker) > 0 && !strings.HasPrefix(txt, "/"+d.marker+"/") {
+		return nil, false
+	}
+
+	idx := slashSplit.FindAllStringSubmatchIndex(txt, -1)
+	if len(idx) == 0 || idx[0][0] != 0 {
+		return nil, false
+	}
+
+	det := map[string]string{}
+	for i, m := range idx {
+		end := len(txt)
+		if i+1 < len(idx) {
+			end = idx[i+1][0]
+		}
+		det[txt[m[2]:m[3]]] = strings.TrimSuffix(txt[m[1]:end], "/")
+	}
+	return det, true
+}
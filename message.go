@@ -0,0 +1,33 @@
+package mtparser
+
+import "encoding/json"
+
+// MarshalJSON encodes the message as blocks of tags. Tags that repeat in the
+// text block are encoded as arrays of nodes.
+func (m Message) MarshalJSON() ([]byte, error) {
+	out := map[string]map[string]interface{}{}
+
+	for k, blk := range m.Map {
+		out[k] = map[string]interface{}{}
+		for t, nd := range blk {
+			out[k][t] = nd
+		}
+	}
+
+	if len(m.Body) > 0 {
+		bdy := map[string]interface{}{}
+		for _, nd := range m.Body {
+			switch v := bdy[nd.Key].(type) {
+			case nil:
+				bdy[nd.Key] = nd
+			case Node:
+				bdy[nd.Key] = []Node{v, nd}
+			case []Node:
+				bdy[nd.Key] = append(v, nd)
+			}
+		}
+		out["4"] = bdy
+	}
+
+	return json.Marshal(out)
+}
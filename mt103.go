@@ -0,0 +1,140 @@
+package mtparser
+
+import (
+	"errors"
+	"math/big"
+	"time"
+)
+
+// InstructionCode is a field 23E instruction with its additional information.
+type InstructionCode struct {
+	Code string
+	Info string
+}
+
+// MT103 is a single customer credit transfer. Optional parties and amounts
+// are nil when absent.
+type MT103 struct {
+	SenderReference               string
+	TimeIndications               []string
+	BankOperationCode             string
+	InstructionCodes              []InstructionCode
+	TransactionTypeCode           string
+	ValueDate                     time.Time
+	SettledAmount                 Money
+	InstructedAmount              *Money
+	ExchangeRate                  *big.Rat
+	OrderingCustomer              *Party
+	SendingInstitution            *Party
+	OrderingInstitution           *Party
+	SendersCorrespondent          *Party
+	ReceiversCorrespondent        *Party
+	ThirdReimbursementInstitution *Party
+	IntermediaryInstitution       *Party
+	AccountWithInstitution        *Party
+	Beneficiary                   *Party
+	RemittanceInformation         string
+	DetailsOfCharges              string
+	SendersCharges                []Money
+	ReceiversCharges              *Money
+	SenderToReceiverInformation   string
+	RegulatoryReporting           string
+}
+
+// NewMT103 builds a MT103 from a parsed message.
+func NewMT103(m *Message) (*MT103, error) {
+	if mt := m.Map["2"]["type"].Val; mt != "103" {
+		return nil, errors.New("Message type MT" + mt + " is not MT103")
+	}
+	if err := m.ParseBody(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	t := &MT103{
+		OrderingCustomer:              m.party("50", "A", "F", "K"),
+		SendingInstitution:            m.party("51", "A"),
+		OrderingInstitution:           m.party("52", "A", "D"),
+		SendersCorrespondent:          m.party("53", "A", "B", "D"),
+		ReceiversCorrespondent:        m.party("54", "A", "B", "D"),
+		ThirdReimbursementInstitution: m.party("55", "A", "B", "D"),
+		IntermediaryInstitution:       m.party("56", "A", "C", "D"),
+		AccountWithInstitution:        m.party("57", "A", "B", "C", "D"),
+		Beneficiary:                   m.party("59", "", "A", "F"),
+	}
+
+	fld, ok := m.Field("20")
+	if !ok {
+		return nil, errors.New("Field 20 is missing")
+	}
+	t.SenderReference = fld.Val
+
+	for _, fld := range m.Fields("13C") {
+		t.TimeIndications = append(t.TimeIndications, fld.Val)
+	}
+	if fld, ok := m.Field("23B"); ok {
+		t.BankOperationCode = fld.Det["Function"]
+	}
+	for _, fld := range m.Fields("23E") {
+		t.InstructionCodes = append(t.InstructionCodes, InstructionCode{
+			Code: fld.Det["Function"],
+			Info: fld.Det["AdditionalInformation"],
+		})
+	}
+	if fld, ok := m.Field("26T"); ok {
+		t.TransactionTypeCode = fld.Val
+	}
+
+	fld, ok = m.Field("32A")
+	if !ok {
+		return nil, errors.New("Field 32A is missing")
+	}
+	if t.ValueDate, err = ParseDate(fld.Det["Date"]); err != nil {
+		return nil, err
+	}
+	if t.SettledAmount, err = money(fld.Det["Currency"], fld.Det["Amount"]); err != nil {
+		return nil, err
+	}
+
+	if fld, ok := m.Field("33B"); ok {
+		amt, err := money(fld.Det["Code"], fld.Det["Amount"])
+		if err != nil {
+			return nil, err
+		}
+		t.InstructedAmount = &amt
+	}
+	if fld, ok := m.Field("36"); ok {
+		if t.ExchangeRate, err = ParseAmount(fld.Det["Rate"]); err != nil {
+			return nil, err
+		}
+	}
+
+	if fld, ok := m.Field("70"); ok {
+		t.RemittanceInformation = fld.Val
+	}
+	if fld, ok := m.Field("71A"); ok {
+		t.DetailsOfCharges = fld.Val
+	}
+	for _, fld := range m.Fields("71F") {
+		amt, err := money(fld.Det["Code"], fld.Det["Amount"])
+		if err != nil {
+			return nil, err
+		}
+		t.SendersCharges = append(t.SendersCharges, amt)
+	}
+	if fld, ok := m.Field("71G"); ok {
+		amt, err := money(fld.Det["Currency"], fld.Det["Amount"])
+		if err != nil {
+			return nil, err
+		}
+		t.ReceiversCharges = &amt
+	}
+	if fld, ok := m.Field("72"); ok {
+		t.SenderToReceiverInformation = fld.Val
+	}
+	if fld, ok := m.Field("77B"); ok {
+		t.RegulatoryReporting = fld.Val
+	}
+
+	return t, nil
+}
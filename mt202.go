@@ -0,0 +1,161 @@
+package mtparser
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// MT202 is a general financial institution transfer. It also models MT205
+// and sequence A of their COV variants.
+type MT202 struct {
+	TransactionReference        string
+	RelatedReference            string
+	TimeIndications             []string
+	ValueDate                   time.Time
+	SettledAmount               Money
+	OrderingInstitution         *Party
+	SendersCorrespondent        *Party
+	ReceiversCorrespondent      *Party
+	IntermediaryInstitution     *Party
+	AccountWithInstitution      *Party
+	BeneficiaryInstitution      *Party
+	SenderToReceiverInformation string
+}
+
+// UnderlyingCustomer is sequence B of a MT202COV, the customer credit
+// transfer the cover payment is made for.
+type UnderlyingCustomer struct {
+	OrderingCustomer            *Party
+	OrderingInstitution         *Party
+	IntermediaryInstitution     *Party
+	AccountWithInstitution      *Party
+	Beneficiary                 *Party
+	RemittanceInformation       string
+	SenderToReceiverInformation string
+	InstructedAmount            *Money
+}
+
+// MT202COV is a cover payment with the underlying customer credit transfer
+// kept apart from the bank fields of sequence A.
+type MT202COV struct {
+	MT202
+	Underlying UnderlyingCustomer
+}
+
+// IsCover reports whether block 3 flags the message as a cover payment.
+func (m *Message) IsCover() bool {
+	h := m.UserHeader()
+	return h != nil && h.ValidationFlag == "COV"
+}
+
+// CoverSequences splits the text block of a cover payment into sequence A
+// and sequence B, which starts at the ordering customer.
+func (m *Message) CoverSequences() (*Sequence, *Sequence) {
+	a, b := &Sequence{Name: "A"}, &Sequence{Name: "B"}
+	cur := a
+	for _, fld := range m.Body {
+		if slices.Contains([]string{"50A", "50F", "50K"}, fld.Key) {
+			cur = b
+		}
+		cur.Fields = append(cur.Fields, fld)
+	}
+	return a, b
+}
+
+// NewMT202 builds a MT202 or MT205 from a parsed message. For cover
+// payments only sequence A is decoded.
+func NewMT202(m *Message) (*MT202, error) {
+	if mt := m.Map["2"]["type"].Val; mt != "202" && mt != "205" {
+		return nil, errors.New("Message type MT" + mt + " is not MT202 or MT205")
+	}
+	if err := m.ParseBody(); err != nil {
+		return nil, err
+	}
+
+	a, _ := m.CoverSequences()
+	return newMT202(a)
+}
+
+// NewMT202COV builds a MT202COV or MT205COV from a parsed message.
+func NewMT202COV(m *Message) (*MT202COV, error) {
+	if !m.IsCover() {
+		return nil, errors.New("Message is not a cover payment")
+	}
+
+	t, err := NewMT202(m)
+	if err != nil {
+		return nil, err
+	}
+	cov := &MT202COV{MT202: *t}
+
+	_, b := m.CoverSequences()
+	if len(b.Fields) == 0 {
+		return nil, errors.New("Sequence B is missing")
+	}
+
+	cov.Underlying = UnderlyingCustomer{
+		OrderingCustomer:        partyIn(b.Fields, "50", "A", "F", "K"),
+		OrderingInstitution:     partyIn(b.Fields, "52", "A", "D"),
+		IntermediaryInstitution: partyIn(b.Fields, "56", "A", "C", "D"),
+		AccountWithInstitution:  partyIn(b.Fields, "57", "A", "B", "C", "D"),
+		Beneficiary:             partyIn(b.Fields, "59", "", "A", "F"),
+	}
+	if fld := b.Lookup("70"); len(fld) > 0 {
+		cov.Underlying.RemittanceInformation = fld[0].Val
+	}
+	if fld := b.Lookup("72"); len(fld) > 0 {
+		cov.Underlying.SenderToReceiverInformation = fld[0].Val
+	}
+	if fld := b.Lookup("33B"); len(fld) > 0 {
+		amt, err := money(fld[0].Det["Code"], fld[0].Det["Amount"])
+		if err != nil {
+			return nil, err
+		}
+		cov.Underlying.InstructedAmount = &amt
+	}
+
+	return cov, nil
+}
+
+func newMT202(a *Sequence) (*MT202, error) {
+	var err error
+	t := &MT202{
+		OrderingInstitution:     partyIn(a.Fields, "52", "A", "D"),
+		SendersCorrespondent:    partyIn(a.Fields, "53", "A", "B", "D"),
+		ReceiversCorrespondent:  partyIn(a.Fields, "54", "A", "B", "D"),
+		IntermediaryInstitution: partyIn(a.Fields, "56", "A", "D"),
+		AccountWithInstitution:  partyIn(a.Fields, "57", "A", "B", "D"),
+		BeneficiaryInstitution:  partyIn(a.Fields, "58", "A", "D"),
+	}
+
+	fld := a.Lookup("20")
+	if len(fld) == 0 {
+		return nil, errors.New("Field 20 is missing")
+	}
+	t.TransactionReference = fld[0].Val
+
+	if fld := a.Lookup("21"); len(fld) > 0 {
+		t.RelatedReference = fld[0].Val
+	}
+	for _, fld := range a.Lookup("13C") {
+		t.TimeIndications = append(t.TimeIndications, fld.Val)
+	}
+
+	fld = a.Lookup("32A")
+	if len(fld) == 0 {
+		return nil, errors.New("Field 32A is missing")
+	}
+	if t.ValueDate, err = ParseDate(fld[0].Det["Date"]); err != nil {
+		return nil, err
+	}
+	if t.SettledAmount, err = money(fld[0].Det["Currency"], fld[0].Det["Amount"]); err != nil {
+		return nil, err
+	}
+
+	if fld := a.Lookup("72"); len(fld) > 0 {
+		t.SenderToReceiverInformation = fld[0].Val
+	}
+
+	return t, nil
+}
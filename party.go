@@ -0,0 +1,56 @@
+package mtparser
+
+import "strings"
+
+// Party is a decoded party field such as 50K, 52A or 59. Option is the
+// letter of the field option and is empty for fields like 59 without one.
+type Party struct {
+	Tag         string
+	Option      string
+	Mark        string
+	Account     string
+	BIC         string
+	Location    string
+	NameAddress []string
+}
+
+// NewParty decodes a party field from the components ParseBody found in it.
+func NewParty(fld Node) *Party {
+	p := &Party{Tag: fld.Key}
+	if l := fld.Key[len(fld.Key)-1]; l >= 'A' && l <= 'Z' {
+		p.Option = string(l)
+	}
+
+	det := fld.Det
+	p.Mark = det["Mark"]
+	p.Account = det["Account"]
+	p.BIC = det["BankCode"] + det["CountryCode"] + det["LocationCode"] + det["BranchCode"]
+	p.Location = det["Location"]
+	if id, ok := det["PartyIdentifier"]; ok {
+		// The party identifier of option F is either an account or a
+		// code/country/identifier triplet.
+		p.Account = strings.TrimPrefix(id, "/")
+	}
+	if na := det["NameandAddress"]; len(na) > 0 {
+		p.NameAddress = strings.Split(na, "\n")
+	}
+
+	return p
+}
+
+// party returns the first field with tag number num and one of the options
+// in opts, or nil when none is present.
+func (m *Message) party(num string, opts ...string) *Party {
+	return partyIn(m.Body, num, opts...)
+}
+
+func partyIn(flds []Node, num string, opts ...string) *Party {
+	for _, fld := range flds {
+		for _, opt := range opts {
+			if fld.Key == num+opt {
+				return NewParty(fld)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package mtparser
+
+import "errors"
+
+// QualifierTable lists the qualifiers allowed per sequence name and tag with
+// their description. Tags are either a full tag such as "36B" or a tag
+// number followed by "a" to cover every option, as in "98a". Entries holding
+// PartialQualifiers only describe some of the qualifiers allowed.
+type QualifierTable map[string]map[string]map[string]string
+
+// PartialQualifiers marks an entry of a QualifierTable that does not list
+// every qualifier allowed, so that other qualifiers are not rejected.
+const PartialQualifiers = "*"
+
+var settlementQualifiers = QualifierTable{
+	"GENL": {
+		"20C": {"SEME": "Sender's Message Reference"},
+		"98a": {"PREP": "Preparation Date/Time"},
+		"99B": {
+			"SETT": "Current Settlement Instruction Number",
+			"TOSE": "Total of Linked Settlement Instructions",
+		},
+	},
+	"LINK": {
+		"22F": {"LINK": "Linkage Type Indicator"},
+		"13A": {"LINK": "Linked Message"},
+		"20C": {
+			PartialQualifiers: "",
+			"RELA":            "Related Message Reference",
+			"PREV":            "Previous Message Reference",
+			"POOL":            "Pool Reference",
+			"COMM":            "Common Reference",
+			"CORP":            "Corporate Action Reference",
+			"TRRF":            "Deal Reference",
+			"COLR":            "Collateral Reference",
+		},
+	},
+	"TRADDET": {
+		"94a": {
+			PartialQualifiers: "",
+			"TRAD":            "Place of Trade",
+			"CLEA":            "Place of Clearing",
+		},
+		"98a": {
+			PartialQualifiers: "",
+			"SETT":            "Settlement Date/Time",
+			"TRAD":            "Trade Date/Time",
+		},
+		"90a": {"DEAL": "Deal Price"},
+		"99A": {"DAAC": "Number of Days Accrued"},
+		"70E": {
+			"SPRO": "Settlement Instruction Processing Narrative",
+			"FIAN": "Financial Instrument Attribute Narrative",
+		},
+	},
+	"FIAC": {
+		"36B": {"SETT": "Quantity of Financial Instrument to be Settled"},
+		"95a": {"ACOW": "Account Owner"},
+		"97a": {
+			"SAFE": "Safekeeping Account",
+			"CASH": "Cash Account",
+		},
+		"94a": {"SAFE": "Place of Safekeeping"},
+	},
+	"SETDET": {
+		"22F": {
+			PartialQualifiers: "",
+			"SETR":            "Type of Settlement Transaction Indicator",
+			"STCO":            "Settlement Transaction Condition Indicator",
+			"RTGS":            "Securities Real-Time Gross Settlement Indicator",
+			"BENE":            "Beneficial Ownership Indicator",
+			"CASY":            "Cash Settlement System Indicator",
+			"STAM":            "Stamp Duty Indicator",
+			"REGT":            "Registration Indicator",
+		},
+	},
+	"SETPRTY": {
+		"95a": {
+			PartialQualifiers: "",
+			"DEAG":            "Delivering Agent",
+			"REAG":            "Receiving Agent",
+			"DECU":            "Deliverer's Custodian",
+			"RECU":            "Receiver's Custodian",
+			"DEI1":            "Deliverer's Intermediary 1",
+			"DEI2":            "Deliverer's Intermediary 2",
+			"REI1":            "Receiver's Intermediary 1",
+			"REI2":            "Receiver's Intermediary 2",
+			"SELL":            "Seller",
+			"BUYR":            "Buyer",
+			"PSET":            "Place of Settlement",
+		},
+		"97a": {
+			"SAFE": "Safekeeping Account",
+			"CASH": "Cash Account",
+		},
+		"98a": {"PROC": "Processing Date/Time"},
+		"20C": {"PROC": "Processing Reference"},
+	},
+	"CSHPRTY": {
+		"95a": {
+			"ACCW": "Account With Institution",
+			"BENM": "Beneficiary of Money",
+			"PAYE": "Paying Institution",
+			"DEBT": "Debtor",
+			"INTM": "Intermediary",
+		},
+		"97a": {
+			"CASH": "Cash Account",
+			"CHAR": "Charges Account",
+			"COMM": "Commission Account",
+			"TAXE": "Tax Account",
+		},
+	},
+	"AMT": {
+		"17B": {
+			"ACRU": "Accrued Interest Indicator",
+			"STAM": "Stamp Duty Indicator",
+		},
+		"19A": {
+			PartialQualifiers: "",
+			"SETT":            "Settlement Amount",
+			"DEAL":            "Trade Amount",
+			"ACRU":            "Accrued Interest Amount",
+			"CHAR":            "Charges/Fees",
+			"EXEC":            "Executing Broker's Commission",
+			"LOCL":            "Local Tax",
+			"OTHR":            "Other Amount",
+			"REGF":            "Regulatory Fees",
+			"STAM":            "Stamp Duty",
+			"TRAX":            "Transaction Tax",
+			"VATA":            "Value-Added Tax",
+			"WITH":            "Withholding Tax",
+		},
+		"98a": {"VALU": "Value Date/Time"},
+		"92B": {"EXCH": "Exchange Rate"},
+	},
+}
+
+// Qualifiers holds the qualifier dictionary per message type.
+var Qualifiers = map[string]QualifierTable{
+	"540": settlementQualifiers,
+	"541": settlementQualifiers,
+	"542": settlementQualifiers,
+	"543": settlementQualifiers,
+}
+
+// lookup returns the qualifiers allowed for tag in sequence seq.
+func (t QualifierTable) lookup(seq string, tag string) (map[string]string, bool) {
+	tgs, ok := t[seq]
+	if !ok {
+		return nil, false
+	}
+	if qls, ok := tgs[tag]; ok {
+		return qls, true
+	}
+	if len(tag) == 3 {
+		qls, ok := tgs[tag[:2]+"a"]
+		return qls, ok
+	}
+	return nil, false
+}
+
+// QualifierDescription describes a qualifier of tag in sequence seq of a
+// message type, or returns an empty string when it is not known.
+func QualifierDescription(mt string, seq string, tag string, qual string) string {
+	if qual == PartialQualifiers {
+		return ""
+	}
+	qls, _ := Qualifiers[mt].lookup(seq, tag)
+	return qls[qual]
+}
+
+// ValidateQualifiers checks that each qualifier is allowed for its tag in the
+// sequence it appears in. Sequences and tags missing from the dictionary and
+// partial entries are not checked.
+func (m *Message) ValidateQualifiers() error {
+	tbl, ok := Qualifiers[m.Map["2"]["type"].Val]
+	if !ok {
+		return nil
+	}
+
+	root, err := m.Sequences()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	var walk func(seq *Sequence)
+	walk = func(seq *Sequence) {
+		for _, fld := range seq.Fields {
+			qls, ok := tbl.lookup(seq.Name, fld.Key)
+			if _, partial := qls[PartialQualifiers]; !ok || partial {
+				continue
+			}
+			ql := Qualifier(fld)
+			if _, ok := qls[ql]; !ok {
+				errs = append(errs, errors.New("Qualifier "+ql+" is not allowed for tag "+fld.Key+" in sequence "+seq.Name))
+			}
+		}
+		for _, sub := range seq.Sequences {
+			walk(sub)
+		}
+	}
+	walk(root)
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,139 @@
+package mtparser
+
+import (
+	"bufio"
+	"io"
+	"iter"
+	"text/scanner"
+)
+
+// Reader parses a stream of back to back MT messages one message at a time.
+type Reader struct {
+	psr     Parser
+	err     error
+	pending bool
+	// held is a message read after an ACK or NAK that was not the message
+	// it refers to, returned with heldErr by the following call to Next.
+	held    *Message
+	heldErr error
+	// Tolerant makes Next return each message with all the errors found in
+	// it rather than stopping at the first one.
+	Tolerant bool
+	// Registry and Release are set on every message returned by Next.
+	Registry *Registry
+	Release  string
+}
+
+func NewReader(r io.Reader) *Reader {
+	psr, err := New(bufio.NewReader(r))
+	return &Reader{psr: psr, err: err}
+}
+
+// Next returns the next message in the stream, or io.EOF once the stream
+// is exhausted. A message ends when the next basic header block starts.
+// In tolerant mode the partial message is returned along with an ErrorList.
+// An ACK or NAK gets the user message that follows it as Original. Any
+// other message following it, or the error reading it, is returned by the
+// next call instead.
+func (r *Reader) Next() (*Message, error) {
+	msg, err := r.held, r.heldErr
+	r.held, r.heldErr = nil, nil
+	if msg == nil {
+		if err != nil {
+			return nil, err
+		}
+		if msg, err = r.next(); msg == nil {
+			return nil, err
+		}
+	}
+
+	if r.pending && msg.Map["1"]["service"].Val == "21" {
+		orig, oerr := r.next()
+		switch {
+		case orig != nil && orig.Map["1"]["service"].Val == "01":
+			msg.Original = orig
+			if err == nil {
+				err = oerr
+			}
+		case oerr != io.EOF:
+			r.held, r.heldErr = orig, oerr
+		}
+	}
+
+	return msg, err
+}
+
+// next parses the next message in the stream.
+func (r *Reader) next() (*Message, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	s := &r.psr
+	s.Tolerant = r.Tolerant
+	s.Registry = r.Registry
+	s.Release = r.Release
+	s.Blocks = []Block{}
+	s.Map = ParserMap{}
+	s.Body = []Node{}
+	s.errs = nil
+
+	// The message only starts with its first block, so that errors before
+	// it do not make up a message of their own.
+	started := r.pending
+	for {
+		if !r.pending {
+			s.skipSeparators()
+			if s.Peek() == scanner.EOF {
+				if !started {
+					r.err = io.EOF
+					if len(s.errs) > 0 {
+						return nil, s.errs.Err()
+					}
+					return nil, r.err
+				}
+				break
+			}
+
+			if err := s.scanKey(); err != nil {
+				if r.err = s.tolerate(err); r.err != nil {
+					return nil, r.err
+				}
+				s.skipTo("{")
+				continue
+			}
+			if started && s.blk.Key == "1" {
+				r.pending = true
+				break
+			}
+		}
+		r.pending = false
+		started = true
+
+		if err := s.scanBlock(); err != nil {
+			if r.err = s.tolerate(err); r.err != nil {
+				return nil, r.err
+			}
+			s.skipTo("{")
+		}
+	}
+
+	msg := s.Message
+	return &msg, s.errs.Err()
+}
+
+// All iterates over the remaining messages in the stream. Iteration stops
+// after the first error that ends the stream.
+func (r *Reader) All() iter.Seq2[*Message, error] {
+	return func(yield func(*Message, error) bool) {
+		for {
+			msg, err := r.Next()
+			if err == io.EOF {
+				return
+			}
+			if !yield(msg, err) || msg == nil || r.err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package mtparser
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+const ackMessage = `{1:F21AAAAGRA0AXXX0057000289}{4:{177:0003210920}{451:0}}`
+
+// nextAll calls Next until io.EOF or an error and describes each result as the
+// reference of the message, "ACK" for a service message followed by the
+// reference of its original, or "error".
+func nextAll(t *testing.T, in string) string {
+	t.Helper()
+	r := NewReader(strings.NewReader(in))
+	var res []string
+	for i := 0; i < 10; i++ {
+		msg, err := r.Next()
+		switch {
+		case err == io.EOF:
+			return strings.Join(res, ",")
+		case msg == nil:
+			// Errors that are not tolerated end the stream.
+			return strings.Join(append(res, "error"), ",")
+		case msg.IsService():
+			ack := "ACK"
+			if msg.Original != nil {
+				ack += ">" + msg.Original.Map["4"]["20"].Val
+			}
+			if err != nil {
+				ack += "!"
+			}
+			res = append(res, ack)
+		default:
+			res = append(res, msg.Map["4"]["20"].Val)
+		}
+	}
+	t.Fatal("reader did not reach EOF")
+	return ""
+}
+
+func TestReaderAck(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"with original", ackMessage + readmeMT103 + secondMT103, "ACK>5387354,SECOND"},
+		{"followed by ack", ackMessage + ackMessage + readmeMT103, "ACK,ACK>5387354"},
+		{"at eof", readmeMT103 + ackMessage, "5387354,ACK"},
+		{"followed by error", ackMessage + "{1:x}" + readmeMT103, "ACK,error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextAll(t, tt.in); got != tt.want {
+				t.Errorf("Next = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package mtparser
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+)
+
+// BalanceMismatch is returned by Reconcile when the amount of field Tag does
+// not equal what the statement lines add up to.
+type BalanceMismatch struct {
+	Tag        string
+	Expected   *big.Rat
+	Actual     *big.Rat
+	Difference *big.Rat
+}
+
+func (e *BalanceMismatch) Error() string {
+	return "Field " + e.Tag + " is " + FormatAmount(e.Actual) + " but the entries add up to " +
+		FormatAmount(e.Expected) + ", a difference of " + FormatAmount(e.Difference)
+}
+
+// Reconcile checks in exact decimals that the statement lines account for the
+// difference between the opening and closing balances, that the currencies
+// and marks of the balances agree and, for a MT942, that the entry
+// summaries match the lines.
+func (st *Statement) Reconcile() error {
+	var errs []error
+
+	ccy := ""
+	switch {
+	case st.Opening != nil:
+		ccy = st.Opening.Currency
+	case st.Closing != nil:
+		ccy = st.Closing.Currency
+	case len(st.FloorLimits) > 0:
+		ccy = st.FloorLimits[0].Currency
+	}
+
+	for _, b := range st.balances() {
+		if !validMark(b.Mark) {
+			errs = append(errs, errors.New("Balance "+b.Tag+" has an invalid mark '"+b.Mark+"'"))
+		}
+		if b.Currency != ccy {
+			errs = append(errs, errors.New("Balance "+b.Tag+" is in "+b.Currency+" but the statement is in "+ccy))
+		}
+	}
+
+	for i, ln := range st.Lines {
+		if len(ln.FundsCode) > 0 && len(ccy) == 3 && ln.FundsCode != ccy[2:] {
+			errs = append(errs, errors.New("Statement line "+strconv.Itoa(i+1)+" has funds code "+ln.FundsCode+" which does not match "+ccy))
+		}
+	}
+
+	if st.Type != "942" {
+		if st.Opening == nil || st.Closing == nil {
+			errs = append(errs, errors.New("Statement needs an opening and a closing balance to reconcile"))
+			return errors.Join(errs...)
+		}
+
+		exp := st.Opening.Signed()
+		for _, ln := range st.Lines {
+			exp.Add(exp, ln.Signed())
+		}
+		act := st.Closing.Signed()
+
+		switch {
+		case exp.Cmp(act) == 0:
+		case new(big.Rat).Abs(exp).Cmp(new(big.Rat).Abs(act)) == 0:
+			errs = append(errs, errors.New("Closing balance has mark "+st.Closing.Mark+" but the entries add up to the opposite sign"))
+		default:
+			errs = append(errs, &BalanceMismatch{
+				Tag:        st.Closing.Tag,
+				Expected:   exp,
+				Actual:     act,
+				Difference: new(big.Rat).Sub(act, exp),
+			})
+		}
+
+		for _, b := range st.ForwardAvailable {
+			if b.Date.Before(st.Closing.Date) {
+				errs = append(errs, errors.New("Forward available balance is dated before the closing balance"))
+			}
+		}
+	}
+
+	if st.Debits != nil || st.Credits != nil {
+		dbt, crd := &EntrySummary{Money: Money{Value: new(big.Rat)}}, &EntrySummary{Money: Money{Value: new(big.Rat)}}
+		for _, ln := range st.Lines {
+			sum := crd
+			if ln.Signed().Sign() < 0 {
+				sum = dbt
+			}
+			sum.Count++
+			sum.Value.Add(sum.Value, ln.Amount)
+		}
+		errs = append(errs, checkSummary("90D", st.Debits, dbt, ccy)...)
+		errs = append(errs, checkSummary("90C", st.Credits, crd, ccy)...)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (st *Statement) balances() []*Balance {
+	var bls []*Balance
+	for _, b := range []*Balance{st.Opening, st.Closing, st.ClosingAvailable} {
+		if b != nil {
+			bls = append(bls, b)
+		}
+	}
+	for i := range st.ForwardAvailable {
+		bls = append(bls, &st.ForwardAvailable[i])
+	}
+	return bls
+}
+
+func checkSummary(tag string, got *EntrySummary, exp *EntrySummary, ccy string) []error {
+	var errs []error
+	if got == nil {
+		return nil
+	}
+	if got.Currency != ccy {
+		errs = append(errs, errors.New("Field "+tag+" is in "+got.Currency+" but the statement is in "+ccy))
+	}
+	if got.Count != exp.Count {
+		errs = append(errs, errors.New("Field "+tag+" counts "+strconv.Itoa(got.Count)+" entries but the statement has "+strconv.Itoa(exp.Count)))
+	}
+	if got.Value.Cmp(exp.Value) != 0 {
+		errs = append(errs, &BalanceMismatch{
+			Tag:        tag,
+			Expected:   exp.Value,
+			Actual:     got.Value,
+			Difference: new(big.Rat).Sub(got.Value, exp.Value),
+		})
+	}
+	return errs
+}
+
+func validMark(m string) bool {
+	return m == "C" || m == "D"
+}
@@ -0,0 +1,148 @@
+package mtparser
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Registry holds field patterns by message type and tag. Patterns
+// registered for the empty message type apply to every message type that
+// has no pattern of its own. A registry made with NewRegistryFrom then
+// looks in its parent, and the base table is used last.
+type Registry struct {
+	mu         sync.RWMutex
+	patterns   map[string]map[string]map[string]string
+	structures map[string]*MessageSpec
+	parent     *Registry
+	base       map[string]map[string]string
+}
+
+// DefaultRegistry is used by messages that have no Registry of their own.
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry returns a registry with the built-in spec set, using
+// FieldPatterns as its base table.
+func NewRegistry() *Registry {
+	r := &Registry{
+		patterns:   map[string]map[string]map[string]string{},
+		structures: map[string]*MessageSpec{},
+		base:       FieldPatterns,
+	}
+	spec := BuiltinSpec()
+	spec.Fields = nil
+	spec.install(r)
+	return r
+}
+
+// NewRegistryFrom returns an empty registry that falls back to parent for
+// the patterns and structures it does not have, so that later changes to
+// parent still apply.
+func NewRegistryFrom(parent *Registry) *Registry {
+	return &Registry{
+		patterns:   map[string]map[string]map[string]string{},
+		structures: map[string]*MessageSpec{},
+		parent:     parent,
+	}
+}
+
+// Register sets the pattern of tag for message type mt, or for all message
+// types when mt is empty. The pattern is checked with ParseFormat first.
+func (r *Registry) Register(mt string, tag string, pattern string, names string) error {
+	if _, err := ParseFormat(pattern, names); err != nil {
+		return err
+	}
+	r.set(mt, tag, map[string]string{
+		"pattern":    pattern,
+		"fieldNames": names,
+	})
+	return nil
+}
+
+func (r *Registry) set(mt string, tag string, ptn map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.patterns[mt] == nil {
+		r.patterns[mt] = map[string]map[string]string{}
+	}
+	r.patterns[mt][tag] = ptn
+}
+
+// Lookup returns the pattern of tag for message type mt. The returned map
+// is shared and must not be modified.
+func (r *Registry) Lookup(mt string, tag string) (map[string]string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if ptn, ok := r.patterns[mt][tag]; ok {
+		return ptn, true
+	}
+	if ptn, ok := r.patterns[""][tag]; ok {
+		return ptn, true
+	}
+	if r.parent != nil {
+		return r.parent.Lookup(mt, tag)
+	}
+	ptn, ok := r.base[tag]
+	return ptn, ok
+}
+
+func (r *Registry) setStructure(mt string, ms *MessageSpec) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.structures[mt] = ms
+}
+
+// Structure returns the structure of message type mt. The returned spec is
+// shared and must not be modified.
+func (r *Registry) Structure(mt string) (*MessageSpec, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if ms, ok := r.structures[mt]; ok {
+		return ms, true
+	}
+	if r.parent != nil {
+		return r.parent.Structure(mt)
+	}
+	return nil, false
+}
+
+// RegisterPattern registers a pattern with DefaultRegistry.
+func RegisterPattern(mt string, tag string, pattern string, names string) error {
+	return DefaultRegistry.Register(mt, tag, pattern, names)
+}
+
+// registry returns the registry used to decode the fields of m. Registry
+// takes precedence over Release.
+func (m *Message) registry() (*Registry, error) {
+	if m.Registry != nil {
+		return m.Registry, nil
+	}
+
+	switch m.Release {
+	case "":
+		return DefaultRegistry, nil
+	case AutoRelease:
+		t, ok := m.sentAt()
+		if !ok {
+			t = time.Now()
+		}
+		if rel, ok := ReleaseAt(t); ok {
+			return rel.Registry, nil
+		}
+		return DefaultRegistry, nil
+	}
+
+	if rel, ok := LookupRelease(m.Release); ok {
+		return rel.Registry, nil
+	}
+	return nil, errors.New("Unknown standards release " + m.Release)
+}
+
+// messageType returns the message type from the application header.
+func (m *Message) messageType() string {
+	return m.Map["2"]["type"].Val
+}
@@ -0,0 +1,106 @@
+package mtparser
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// AutoRelease selects the standards release in effect on the date a message
+// was sent, taken from its output application header. Input messages carry
+// no date and use the release in effect now, and messages older than every
+// release use DefaultRegistry. No releases are built in, they are added
+// with InstallRelease or RegisterRelease.
+const AutoRelease = "auto"
+
+// Release is a named set of field formats and message structures that
+// applies from its effective date.
+type Release struct {
+	Name      string
+	Effective time.Time
+	Registry  *Registry
+}
+
+var (
+	releaseMu sync.RWMutex
+	releases  []*Release
+)
+
+// RegisterRelease adds a standards release, replacing any release of the
+// same name. A registry made with NewRegistryFrom(DefaultRegistry) keeps
+// the patterns registered with RegisterPattern.
+func RegisterRelease(name string, effective time.Time, r *Registry) {
+	releaseMu.Lock()
+	defer releaseMu.Unlock()
+
+	releases = slices.DeleteFunc(releases, func(rel *Release) bool {
+		return rel.Name == name
+	})
+	releases = append(releases, &Release{Name: name, Effective: effective, Registry: r})
+	slices.SortStableFunc(releases, func(a, b *Release) int {
+		return a.Effective.Compare(b.Effective)
+	})
+}
+
+// LookupRelease returns the standards release called name.
+func LookupRelease(name string) (*Release, bool) {
+	releaseMu.RLock()
+	defer releaseMu.RUnlock()
+
+	for _, rel := range releases {
+		if rel.Name == name {
+			return rel, true
+		}
+	}
+	return nil, false
+}
+
+// ReleaseAt returns the latest standards release in effect at t.
+func ReleaseAt(t time.Time) (*Release, bool) {
+	releaseMu.RLock()
+	defer releaseMu.RUnlock()
+
+	for i := len(releases) - 1; i >= 0; i-- {
+		if !releases[i].Effective.After(t) {
+			return releases[i], true
+		}
+	}
+	return nil, false
+}
+
+// Releases returns the standards releases ordered by effective date.
+func Releases() []*Release {
+	releaseMu.RLock()
+	defer releaseMu.RUnlock()
+
+	return slices.Clone(releases)
+}
+
+// InstallRelease installs the spec on top of DefaultRegistry and registers it
+// as the standards release named by its version.
+func (s *Spec) InstallRelease(effective time.Time) (*Release, error) {
+	if len(s.Version) == 0 {
+		return nil, errors.New("Spec " + s.Name + " has no version")
+	}
+
+	r := NewRegistryFrom(DefaultRegistry)
+	if err := s.Install(r); err != nil {
+		return nil, err
+	}
+	RegisterRelease(s.Version, effective, r)
+
+	rel, _ := LookupRelease(s.Version)
+	return rel, nil
+}
+
+// sentAt returns the date the message was sent, from the output application
+// header.
+func (m *Message) sentAt() (time.Time, bool) {
+	v, ok := m.Map["2"]["input_ddmmyy"]
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := ParseDate(v.Val)
+	return t, err == nil
+}
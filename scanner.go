@@ -2,25 +2,17 @@ package mtparser
 
 import (
 	"bufio"
-	"errors"
 	"io"
-	"strconv"
-	"strings"
 	"text/scanner"
 )
 
 func New(r *bufio.Reader) (Parser, error) {
-	data, err := io.ReadAll(r)
-	if err != nil {
-		return Parser{}, err
-	}
-	// We remove carriage returns from the data before parsing
-	// because Windows uses \r\n as line endings and we only want \n.
-	clean := strings.ReplaceAll(string(data), "\r", "")
-	newData := bufio.NewReader(strings.NewReader(clean))
-
 	var s Parser
-	s.Init(newData)
+	// Carriage returns are removed while the scanner pulls data because
+	// Windows uses \r\n as line endings and we only want \n. The input is
+	// never read into memory as a whole.
+	s.cr = &crReader{r: r}
+	s.Init(s.cr)
 	s.Mode = scanner.ScanIdents
 	s.Whitespace = 1<<'\t' | 1<<'\r'
 	s.IsIdentRune = func(ch rune, i int) bool {
@@ -35,55 +27,147 @@ func New(r *bufio.Reader) (Parser, error) {
 	return s, nil
 }
 
+// crWindow is how far behind the data handed out crReader keeps track of
+// carriage returns. The scanner buffers much less than this.
+const crWindow = 4096
+
+// crReader drops carriage returns from the underlying reader and remembers
+// where it did so that offsets can be mapped back to the input.
+type crReader struct {
+	r io.Reader
+	n int
+	// crs holds the output offsets of the carriage returns dropped in the
+	// last crWindow bytes, and base counts those dropped before.
+	crs  []int
+	base int
+}
+
+func (c *crReader) Read(p []byte) (int, error) {
+	for {
+		n, err := c.r.Read(p)
+		j := 0
+		for _, b := range p[:n] {
+			if b != '\r' {
+				p[j] = b
+				j++
+			} else {
+				c.crs = append(c.crs, c.n+j)
+			}
+		}
+		c.n += j
+		for len(c.crs) > 0 && c.crs[0] < c.n-crWindow {
+			c.crs = c.crs[1:]
+			c.base++
+		}
+		if j > 0 || n == 0 || err != nil {
+			return j, err
+		}
+	}
+}
+
+// offset maps an offset in the data handed out to one in the input.
+func (c *crReader) offset(off int) int {
+	n := c.base
+	for _, o := range c.crs {
+		if o > off {
+			break
+		}
+		n++
+	}
+	return off + n
+}
+
 func (s *Parser) ErrMessage(c rune, x bool) string {
-	ln := strconv.Itoa(s.Pos().Line)
-	cl := strconv.Itoa(s.Pos().Column)
-	xp := "Expected"
 	if !x {
-		xp = "Unexpected"
+		return s.newError(ErrUnexpected, 0, c).Error()
 	}
-	return s.ErrPrefix + " " + xp + " '" + string(c) + "' at line " + ln + " column " + cl
+	return s.newError(ErrExpected, c, 0).Error()
 }
 
 func (s *Parser) Parse() error {
 	var err error
 
+	s.errs = nil
 	for s.Peek() != scanner.EOF {
-		if s.Scan() != '{' {
-			return errors.New(s.ErrMessage('{', true))
+		if err = s.scanKey(); err == nil {
+			err = s.scanBlock()
 		}
-
-		s.Scan()
-		s.blk.Key = s.TokenText()
-
-		if s.Scan() != ':' {
-			return errors.New(s.ErrMessage(':', true))
-		}
-
-		switch s.Peek() {
-		case '\n':
-			if err = s.scanBody(); err != nil {
-				return err
-			}
-			break
-		case '{':
-			if err = s.scanBlocks(); err != nil {
+		if err != nil {
+			if err = s.tolerate(err); err != nil {
 				return err
 			}
-			break
-		default:
-			if err = s.scanHeader(); err != nil {
-				return err
-			}
-			break
+			s.skipTo("{")
 		}
+	}
 
-		if s.Scan() != '}' {
-			return errors.New(s.ErrMessage('}', true))
-		}
+	return s.errs.Err()
+}
+
+// scanKey reads the {key: opening a block. Characters are only consumed once
+// they match, so that after an error the input resumes at the one that did
+// not.
+func (s *Parser) scanKey() error {
+	if p := s.Peek(); p != '{' {
+		return s.errExpected('{', p)
+	}
+	s.Next()
+
+	switch p := s.Peek(); p {
+	case '{', '}', ':', '\n', scanner.EOF:
+		return s.errUnexpected(p)
+	}
+	s.Scan()
+	s.blk.Key = s.TokenText()
+	s.tag = ""
+
+	if p := s.Peek(); p != ':' {
+		return s.errExpected(':', p)
+	}
+	s.Next()
+
+	return nil
+}
 
-		s.Blocks = append(s.Blocks, *&s.blk)
+// scanBlock reads the content of a block and its closing brace. In tolerant
+// mode an unclosed block is kept with what was read of it.
+func (s *Parser) scanBlock() error {
+	var err error
+
+	switch s.Peek() {
+	case '\n':
+		err = s.scanBody()
+	case '{':
+		err = s.scanBlocks()
+	default:
+		err = s.scanHeader()
+	}
+
+	if err == nil {
+		if p := s.Peek(); p != '}' {
+			err = s.errExpected('}', p)
+		} else {
+			s.Next()
+		}
+	}
+	if err != nil {
+		if err = s.tolerate(err); err != nil {
+			return err
+		}
 	}
 
+	s.Blocks = append(s.Blocks, *&s.blk)
 	return nil
 }
+
+// skipSeparators consumes the characters found between messages in
+// RJE and DOS-PCC files.
+func (s *Parser) skipSeparators() {
+	for {
+		switch s.Peek() {
+		case '\n', '\r', '\t', ' ', '$', 0x01, 0x03:
+			s.Next()
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package mtparser
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+const secondMT103 = `{1:F01AAAAGRA0AXXX0057000290}{2:I103BBBBGRA0AXXXN}{4:
+:20:SECOND
+:23B:CRED
+:32A:000526USD5,
+:50K:FRANZ HOLZAPFEL GMBH
+:59:C. KLEIN
+:71A:SHA
+-}`
+
+// readAll reads every message of the stream, returning the reference of each
+// one and the number of errors reported along the way.
+func readAll(t *testing.T, in string) (refs []string, errs int) {
+	t.Helper()
+	r := NewReader(strings.NewReader(in))
+	r.Tolerant = true
+	for i := 0; i < 10; i++ {
+		msg, err := r.Next()
+		if err == io.EOF {
+			return refs, errs
+		}
+		if err != nil {
+			errs++
+		}
+		if msg != nil {
+			refs = append(refs, msg.Map["4"]["20"].Val)
+		}
+	}
+	t.Fatal("reader did not reach EOF")
+	return nil, 0
+}
+
+func TestParseResync(t *testing.T) {
+	in := `{1:F01AAAAGRA0AXXX0057000289}{x{2:I103BBBBGRA0AXXXN}{4:
+:20:REF
+-}`
+	psr, err := New(bufio.NewReader(strings.NewReader(in)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	psr.Tolerant = true
+	if err := psr.Parse(); err == nil {
+		t.Fatal("expected an error")
+	}
+	var keys []string
+	for _, blk := range psr.Blocks {
+		keys = append(keys, blk.Key)
+	}
+	if got := strings.Join(keys, ","); got != "1,2,4" {
+		t.Errorf("blocks = %s, want 1,2,4", got)
+	}
+	if got := psr.Map["4"]["20"].Val; got != "REF" {
+		t.Errorf("20 = %q, want REF", got)
+	}
+}
+
+func TestReaderResync(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		refs string
+		errs int
+	}{
+		{"clean", readmeMT103 + secondMT103, "5387354,SECOND", 0},
+		{"junk before first", "junk" + readmeMT103 + secondMT103, "5387354,SECOND", 1},
+		{"bad key between", readmeMT103 + "{x" + secondMT103, "5387354,SECOND", 1},
+		{"junk at end", readmeMT103 + "junk", "5387354", 1},
+		{"unclosed body", strings.TrimSuffix(readmeMT103, "-}{5:{MAC:75D138E4}{CHK:DE1B0D71FA96}}") + secondMT103, "5387354,SECOND", 1},
+		{"unclosed sub-block", strings.Replace(readmeMT103, "{CHK:DE1B0D71FA96}}", "{CHK:DE1B0D71FA96", 1) + secondMT103, "5387354,SECOND", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refs, errs := readAll(t, tt.in)
+			if got := strings.Join(refs, ","); got != tt.refs {
+				t.Errorf("messages = %s, want %s", got, tt.refs)
+			}
+			if errs != tt.errs {
+				t.Errorf("errors = %d, want %d", errs, tt.errs)
+			}
+		})
+	}
+}
+
+func TestParseErrorOffset(t *testing.T) {
+	for _, eol := range []string{"\n", "\r\n"} {
+		in := strings.ReplaceAll(strings.Repeat(readmeMT103, 200)+"{1:x", "\n", eol)
+		r := NewReader(strings.NewReader(in))
+		var err error
+		for err == nil {
+			_, err = r.Next()
+		}
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Fatalf("%q: got %v, want a ParseError", eol, err)
+		}
+		if want := len(in); perr.Offset != want {
+			t.Errorf("%q: Offset = %d, want %d", eol, perr.Offset, want)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package mtparser
+
+import (
+	"errors"
+	"strings"
+)
+
+// Sequence is a named group of text block fields. ISO 15022 messages open
+// and close sequences with fields 16R and 16S, which are not kept in Fields.
+type Sequence struct {
+	Name      string
+	Fields    []Node
+	Sequences []*Sequence
+}
+
+// Sequences returns the text block as a tree of sequences. The root has no
+// name and holds the fields found outside of any sequence.
+func (m *Message) Sequences() (*Sequence, error) {
+	root := &Sequence{}
+	stk := []*Sequence{root}
+
+	for _, fld := range m.Body {
+		cur := stk[len(stk)-1]
+
+		switch fld.Key {
+		case "16R":
+			seq := &Sequence{Name: fld.Val}
+			cur.Sequences = append(cur.Sequences, seq)
+			stk = append(stk, seq)
+		case "16S":
+			if cur.Name != fld.Val || len(stk) == 1 {
+				return root, errors.New("Unexpected end of sequence " + fld.Val)
+			}
+			stk = stk[:len(stk)-1]
+		default:
+			cur.Fields = append(cur.Fields, fld)
+		}
+	}
+
+	if len(stk) > 1 {
+		return root, errors.New("Sequence " + stk[len(stk)-1].Name + " is not closed")
+	}
+	return root, nil
+}
+
+// Find returns every sequence matching a path of sequence names such as
+// "GENL/LINK".
+func (q *Sequence) Find(path string) []*Sequence {
+	seqs := []*Sequence{q}
+	if len(path) == 0 {
+		return seqs
+	}
+
+	for _, nm := range strings.Split(path, "/") {
+		var nxt []*Sequence
+		for _, seq := range seqs {
+			for _, sub := range seq.Sequences {
+				if sub.Name == nm {
+					nxt = append(nxt, sub)
+				}
+			}
+		}
+		seqs = nxt
+	}
+	return seqs
+}
+
+// Lookup returns the fields addressed by a path such as "GENL/LINK/20C:PREV",
+// where the last element is a tag optionally followed by a qualifier.
+func (q *Sequence) Lookup(path string) []Node {
+	dir, tag := "", path
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		dir, tag = path[:i], path[i+1:]
+	}
+
+	nds := []Node{}
+	for _, seq := range q.Find(dir) {
+		for _, fld := range seq.Fields {
+			if fieldMatches(fld, tag) {
+				nds = append(nds, fld)
+			}
+		}
+	}
+	return nds
+}
+
+// Lookup builds the sequence tree and returns the fields addressed by path.
+func (m *Message) Lookup(path string) ([]Node, error) {
+	root, err := m.Sequences()
+	if err != nil {
+		return nil, err
+	}
+	return root.Lookup(path), nil
+}
+
+// fieldMatches reports whether fld matches a tag with an optional
+// qualifier, as in "98A" or "98A:SETT".
+func fieldMatches(fld Node, tag string) bool {
+	tg, ql, ok := strings.Cut(tag, ":")
+	if fld.Key != tg {
+		return false
+	}
+	return !ok || Qualifier(fld) == ql
+}
+
+// Qualifier returns the qualifier of a generic field such as ":98A::SETT//",
+// or an empty string for fields without one.
+func Qualifier(fld Node) string {
+	if q, ok := fld.Det["Qualifier"]; ok {
+		return q
+	}
+	if !strings.HasPrefix(fld.Val, ":") {
+		return ""
+	}
+	q, _, ok := strings.Cut(fld.Val[1:], "/")
+	if !ok {
+		return ""
+	}
+	return q
+}
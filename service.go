@@ -0,0 +1,79 @@
+package mtparser
+
+// ErrorCodes describes common FIN error codes returned in tag 405 of a NAK.
+// Callers may add their own entries.
+var ErrorCodes = map[string]string{
+	"D49": "Field 33B is mandatory when the sender and receiver are in the EU/EEA",
+	"D50": "Field 71G is not allowed when field 71A is SHA",
+	"D75": "Field 36 is mandatory when field 33B has a different currency to field 32A",
+	"E01": "Field 23E must be SDVA, TELB, PHOB or INTC when field 23B is SPRI",
+	"E13": "Field 71F is not allowed when field 71A is OUR",
+	"E15": "Field 71F is mandatory and field 71G is not allowed when field 71A is BEN",
+	"T26": "Field must not start or end with a slash or contain two consecutive slashes",
+	"T27": "Invalid BIC",
+	"T50": "Invalid date",
+	"T52": "Invalid currency code",
+}
+
+// ServiceMessage is the decoded form of a FIN service message such as an
+// ACK or NAK. Original is the message it refers to, when it was appended.
+type ServiceMessage struct {
+	Service   string
+	DateTime  string
+	Accepted  bool
+	ErrorCode string
+	ErrorLine string
+	MUR       string
+	MIR       *MessageReference
+	Tags      map[string]string
+	Original  *Message
+}
+
+// IsService reports whether the message is a service message rather than a
+// user to user message.
+func (m *Message) IsService() bool {
+	nd, ok := m.Map["1"]["service"]
+	return ok && nd.Val != "01"
+}
+
+// ServiceMessage decodes a service message, or returns nil when m is a user
+// to user message.
+func (m *Message) ServiceMessage() *ServiceMessage {
+	if !m.IsService() {
+		return nil
+	}
+
+	tgs := blockTags(m.Map["4"])
+	if tgs == nil {
+		tgs = map[string]string{}
+	}
+
+	sm := &ServiceMessage{
+		Service:  m.Map["1"]["service"].Val,
+		DateTime: tgs["177"],
+		Accepted: tgs["451"] == "0",
+		MUR:      tgs["108"],
+		Tags:     tgs,
+		Original: m.Original,
+	}
+
+	if v := tgs["405"]; len(v) >= 3 {
+		sm.ErrorCode = v[:3]
+		sm.ErrorLine = v[3:]
+	}
+
+	if v, ok := tgs["106"]; ok {
+		sm.MIR = messageReference(v)
+	} else if len(sm.DateTime) >= 6 {
+		// The acknowledged message is identified by the date of the ACK
+		// and the session and sequence numbers of its basic header.
+		sm.MIR = messageReference(sm.DateTime[:6] + m.Map["1"]["source"].Val + m.Map["1"]["session"].Val + m.Map["1"]["sequence"].Val)
+	}
+
+	return sm
+}
+
+// ErrorDescription describes the error code of a NAK.
+func (sm *ServiceMessage) ErrorDescription() string {
+	return ErrorCodes[sm.ErrorCode]
+}
@@ -0,0 +1,561 @@
+package mtparser
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec is a set of field formats and message structures, as loaded from a
+// JSON file with LoadSpec:
+//
+//	{
+//	  "name": "inhouse",
+//	  "version": "SR2024",
+//	  "fields": {"72": {"pattern": "6*35x", "fieldNames": "(Narrative)"}},
+//	  "messages": {
+//	    "103": {
+//	      "fields": {"23E": {"pattern": "4!c[/30x]", "fieldNames": "(Function)(Additional Information)"}},
+//	      "sequences": [{"name": "A", "mandatory": true, "fields": [
+//	        {"tag": "20", "mandatory": true},
+//	        {"tag": "50", "options": ["A", "F", "K"], "mandatory": true}
+//	      ]}]
+//	    }
+//	  }
+//	}
+//
+// Fields apply to every message type and use the same keys as
+// FieldPatterns. The fields of a message override them for that type.
+// LoadSpecYAML reads the same layout written in YAML.
+type Spec struct {
+	Name     string                       `json:"name" yaml:"name"`
+	Version  string                       `json:"version,omitempty" yaml:"version,omitempty"`
+	Fields   map[string]map[string]string `json:"fields,omitempty" yaml:"fields,omitempty"`
+	Messages map[string]*MessageSpec      `json:"messages,omitempty" yaml:"messages,omitempty"`
+}
+
+// MessageSpec holds the field formats and structure of one message type.
+// Messages without sequences use a single sequence with an empty name.
+type MessageSpec struct {
+	Fields    map[string]map[string]string `json:"fields,omitempty" yaml:"fields,omitempty"`
+	Sequences []*SequenceSpec              `json:"sequences,omitempty" yaml:"sequences,omitempty"`
+}
+
+// SequenceSpec lists the fields of a sequence in the order they must appear.
+type SequenceSpec struct {
+	Name       string       `json:"name" yaml:"name"`
+	Mandatory  bool         `json:"mandatory,omitempty" yaml:"mandatory,omitempty"`
+	Repeatable bool         `json:"repeatable,omitempty" yaml:"repeatable,omitempty"`
+	Fields     []*FieldSpec `json:"fields" yaml:"fields"`
+}
+
+// FieldSpec is a field of a sequence. Options lists the letters allowed
+// after Tag, with an empty string for the tag on its own, as in 59, 59A and
+// 59F. Without options the tag is used as is.
+type FieldSpec struct {
+	Tag        string   `json:"tag" yaml:"tag"`
+	Options    []string `json:"options,omitempty" yaml:"options,omitempty"`
+	Mandatory  bool     `json:"mandatory,omitempty" yaml:"mandatory,omitempty"`
+	Repeatable bool     `json:"repeatable,omitempty" yaml:"repeatable,omitempty"`
+}
+
+var (
+	specTag    = regexp.MustCompile(`^[0-9]{2}[A-Z]?$`)
+	specOption = regexp.MustCompile(`^[A-Z]?$`)
+)
+
+// builtinMessages holds the message types whose formats differ from
+// FieldPatterns and the built-in message structures.
+var builtinMessages = map[string]*MessageSpec{
+	"101": {
+		Fields: map[string]map[string]string{
+			"23E": {
+				"pattern":    "4!c[/140x]",
+				"fieldNames": "(Function)(Additional Information)",
+			},
+		},
+		Sequences: []*SequenceSpec{{
+			Name:      "A",
+			Mandatory: true,
+			Fields: []*FieldSpec{
+				{Tag: "20", Mandatory: true},
+				{Tag: "21R"},
+				{Tag: "28D", Mandatory: true},
+				// The instructing party comes before the ordering customer.
+				{Tag: "50", Options: []string{"C", "L"}},
+				{Tag: "50", Options: []string{"F", "G", "H"}},
+				{Tag: "52", Options: []string{"A", "C"}},
+				{Tag: "51A"},
+				{Tag: "30", Mandatory: true},
+				{Tag: "25"},
+			},
+		}, {
+			Name:       "B",
+			Mandatory:  true,
+			Repeatable: true,
+			Fields: []*FieldSpec{
+				{Tag: "21", Mandatory: true},
+				{Tag: "21F"},
+				{Tag: "23E", Repeatable: true},
+				{Tag: "32B", Mandatory: true},
+				{Tag: "50", Options: []string{"C", "L"}},
+				{Tag: "50", Options: []string{"F", "G", "H"}},
+				{Tag: "52", Options: []string{"A", "C"}},
+				{Tag: "56", Options: []string{"A", "C", "D"}},
+				{Tag: "57", Options: []string{"A", "C", "D"}},
+				{Tag: "59", Options: []string{"", "A", "F"}, Mandatory: true},
+				{Tag: "70"},
+				{Tag: "77B"},
+				{Tag: "33B"},
+				{Tag: "71A", Mandatory: true},
+				{Tag: "25A"},
+				{Tag: "36"},
+			},
+		}},
+	},
+	"102": {
+		Sequences: []*SequenceSpec{{
+			Name:      "A",
+			Mandatory: true,
+			Fields: []*FieldSpec{
+				{Tag: "20", Mandatory: true},
+				{Tag: "23", Mandatory: true},
+				{Tag: "51A"},
+				{Tag: "50", Options: []string{"A", "F", "K"}},
+				{Tag: "52", Options: []string{"A", "B", "C"}},
+				{Tag: "26T"},
+				{Tag: "77B"},
+				{Tag: "71A"},
+				{Tag: "36"},
+			},
+		}, {
+			Name:       "B",
+			Mandatory:  true,
+			Repeatable: true,
+			Fields: []*FieldSpec{
+				{Tag: "21", Mandatory: true},
+				{Tag: "32B", Mandatory: true},
+				{Tag: "50", Options: []string{"A", "F", "K"}},
+				{Tag: "52", Options: []string{"A", "B", "C"}},
+				{Tag: "57", Options: []string{"A", "C"}},
+				{Tag: "59", Options: []string{"", "A", "F"}, Mandatory: true},
+				{Tag: "70"},
+				{Tag: "26T"},
+				{Tag: "77B"},
+				{Tag: "33B"},
+				{Tag: "71A"},
+				{Tag: "71F", Repeatable: true},
+				{Tag: "71G"},
+				{Tag: "36"},
+			},
+		}, {
+			Name:      "C",
+			Mandatory: true,
+			Fields: []*FieldSpec{
+				{Tag: "32A", Mandatory: true},
+				{Tag: "19"},
+				{Tag: "71G"},
+				{Tag: "13C", Repeatable: true},
+				{Tag: "53", Options: []string{"A", "C"}},
+				{Tag: "54A"},
+				{Tag: "72"},
+			},
+		}},
+	},
+	"103": {
+		Sequences: []*SequenceSpec{{
+			Mandatory: true,
+			Fields: []*FieldSpec{
+				{Tag: "20", Mandatory: true},
+				{Tag: "13C", Repeatable: true},
+				{Tag: "23B", Mandatory: true},
+				{Tag: "23E", Repeatable: true},
+				{Tag: "26T"},
+				{Tag: "32A", Mandatory: true},
+				{Tag: "33B"},
+				{Tag: "36"},
+				{Tag: "50", Options: []string{"A", "F", "K"}, Mandatory: true},
+				{Tag: "51A"},
+				{Tag: "52", Options: []string{"A", "D"}},
+				{Tag: "53", Options: []string{"A", "B", "D"}},
+				{Tag: "54", Options: []string{"A", "B", "D"}},
+				{Tag: "55", Options: []string{"A", "B", "D"}},
+				{Tag: "56", Options: []string{"A", "C", "D"}},
+				{Tag: "57", Options: []string{"A", "B", "C", "D"}},
+				{Tag: "59", Options: []string{"", "A", "F"}, Mandatory: true},
+				{Tag: "70"},
+				{Tag: "71A", Mandatory: true},
+				{Tag: "71F", Repeatable: true},
+				{Tag: "71G"},
+				{Tag: "72"},
+				{Tag: "77B"},
+				{Tag: "77T"},
+			},
+		}},
+	},
+	"104": {
+		Sequences: []*SequenceSpec{{
+			Name:      "A",
+			Mandatory: true,
+			Fields: []*FieldSpec{
+				{Tag: "20", Mandatory: true},
+				{Tag: "21R"},
+				{Tag: "23E"},
+				{Tag: "21E"},
+				{Tag: "30", Mandatory: true},
+				{Tag: "51A"},
+				// The instructing party comes before the creditor.
+				{Tag: "50", Options: []string{"C", "L"}},
+				{Tag: "50", Options: []string{"A", "K"}},
+				{Tag: "52", Options: []string{"A", "C", "D"}},
+				{Tag: "26T"},
+				{Tag: "77B"},
+				{Tag: "71A"},
+				{Tag: "72"},
+			},
+		}, {
+			Name:       "B",
+			Mandatory:  true,
+			Repeatable: true,
+			Fields: []*FieldSpec{
+				{Tag: "21", Mandatory: true},
+				{Tag: "23E"},
+				{Tag: "21C"},
+				{Tag: "21D"},
+				{Tag: "21E"},
+				{Tag: "32B", Mandatory: true},
+				{Tag: "50", Options: []string{"C", "L"}},
+				{Tag: "50", Options: []string{"A", "K"}},
+				{Tag: "52", Options: []string{"A", "C", "D"}},
+				{Tag: "57", Options: []string{"A", "C", "D"}},
+				{Tag: "59", Options: []string{"", "A"}, Mandatory: true},
+				{Tag: "70"},
+				{Tag: "26T"},
+				{Tag: "77B"},
+				{Tag: "33B"},
+				{Tag: "71A"},
+				{Tag: "71F"},
+				{Tag: "71G"},
+				{Tag: "36"},
+			},
+		}, {
+			// Only present when the transactions are settled together.
+			Name: "C",
+			Fields: []*FieldSpec{
+				{Tag: "32B", Mandatory: true},
+				{Tag: "19"},
+				{Tag: "71F"},
+				{Tag: "71G"},
+				{Tag: "53", Options: []string{"A", "B"}},
+			},
+		}},
+	},
+	"202": {
+		Sequences: []*SequenceSpec{{
+			Name:      "A",
+			Mandatory: true,
+			Fields: []*FieldSpec{
+				{Tag: "20", Mandatory: true},
+				{Tag: "21", Mandatory: true},
+				{Tag: "13C", Repeatable: true},
+				{Tag: "32A", Mandatory: true},
+				{Tag: "52", Options: []string{"A", "D"}},
+				{Tag: "53", Options: []string{"A", "B", "D"}},
+				{Tag: "54", Options: []string{"A", "B", "D"}},
+				{Tag: "56", Options: []string{"A", "D"}},
+				{Tag: "57", Options: []string{"A", "B", "D"}},
+				{Tag: "58", Options: []string{"A", "D"}, Mandatory: true},
+				{Tag: "72"},
+			},
+		}, {
+			// Only present in cover payments.
+			Name: "B",
+			Fields: []*FieldSpec{
+				{Tag: "50", Options: []string{"A", "F", "K"}, Mandatory: true},
+				{Tag: "52", Options: []string{"A", "D"}},
+				{Tag: "56", Options: []string{"A", "C", "D"}},
+				{Tag: "57", Options: []string{"A", "B", "C", "D"}},
+				{Tag: "59", Options: []string{"", "A", "F"}, Mandatory: true},
+				{Tag: "70"},
+				{Tag: "72"},
+				{Tag: "33B"},
+			},
+		}},
+	},
+	"205": {
+		Sequences: []*SequenceSpec{{
+			Name:      "A",
+			Mandatory: true,
+			Fields: []*FieldSpec{
+				{Tag: "20", Mandatory: true},
+				{Tag: "21", Mandatory: true},
+				{Tag: "13C", Repeatable: true},
+				{Tag: "32A", Mandatory: true},
+				{Tag: "52", Options: []string{"A", "D"}, Mandatory: true},
+				{Tag: "53", Options: []string{"A", "B", "D"}},
+				{Tag: "56", Options: []string{"A", "D"}},
+				{Tag: "57", Options: []string{"A", "B", "D"}},
+				{Tag: "58", Options: []string{"A", "D"}, Mandatory: true},
+				{Tag: "72"},
+			},
+		}, {
+			// Only present in cover payments.
+			Name: "B",
+			Fields: []*FieldSpec{
+				{Tag: "50", Options: []string{"A", "F", "K"}, Mandatory: true},
+				{Tag: "52", Options: []string{"A", "D"}},
+				{Tag: "56", Options: []string{"A", "C", "D"}},
+				{Tag: "57", Options: []string{"A", "B", "C", "D"}},
+				{Tag: "59", Options: []string{"", "A", "F"}, Mandatory: true},
+				{Tag: "70"},
+				{Tag: "72"},
+				{Tag: "33B"},
+			},
+		}},
+	},
+	"940": {
+		Sequences: []*SequenceSpec{{
+			Mandatory: true,
+			Fields: []*FieldSpec{
+				{Tag: "20", Mandatory: true},
+				{Tag: "21"},
+				{Tag: "25", Options: []string{"", "P"}, Mandatory: true},
+				{Tag: "28C", Mandatory: true},
+				{Tag: "60", Options: []string{"F", "M"}, Mandatory: true},
+			},
+		}, {
+			Name:       "Lines",
+			Repeatable: true,
+			Fields: []*FieldSpec{
+				{Tag: "61", Mandatory: true},
+				{Tag: "86"},
+			},
+		}, {
+			Name:      "Balances",
+			Mandatory: true,
+			Fields: []*FieldSpec{
+				{Tag: "62", Options: []string{"F", "M"}, Mandatory: true},
+				{Tag: "64"},
+				{Tag: "65", Repeatable: true},
+				{Tag: "86"},
+			},
+		}},
+	},
+	"942": {
+		Sequences: []*SequenceSpec{{
+			Mandatory: true,
+			Fields: []*FieldSpec{
+				{Tag: "20", Mandatory: true},
+				{Tag: "21"},
+				{Tag: "25", Options: []string{"", "P"}, Mandatory: true},
+				{Tag: "28C", Mandatory: true},
+				// The debit floor limit, followed by the credit one when
+				// they differ.
+				{Tag: "34F", Mandatory: true},
+				{Tag: "34F"},
+				{Tag: "13D", Mandatory: true},
+			},
+		}, {
+			Name:       "Lines",
+			Repeatable: true,
+			Fields: []*FieldSpec{
+				{Tag: "61", Mandatory: true},
+				{Tag: "86"},
+			},
+		}, {
+			Name: "Summary",
+			Fields: []*FieldSpec{
+				{Tag: "90D"},
+				{Tag: "90C"},
+				{Tag: "86"},
+			},
+		}},
+	},
+	"950": {
+		Sequences: []*SequenceSpec{{
+			Mandatory: true,
+			Fields: []*FieldSpec{
+				{Tag: "20", Mandatory: true},
+				{Tag: "25", Mandatory: true},
+				{Tag: "28C", Mandatory: true},
+				{Tag: "60", Options: []string{"F", "M"}, Mandatory: true},
+				{Tag: "61", Repeatable: true},
+				{Tag: "62", Options: []string{"F", "M"}, Mandatory: true},
+				{Tag: "64"},
+				{Tag: "65", Repeatable: true},
+			},
+		}},
+	},
+}
+
+// BuiltinSpec returns the default spec set NewRegistry starts from. It
+// shares its maps with FieldPatterns and must not be modified.
+func BuiltinSpec() *Spec {
+	return &Spec{
+		Name:     "builtin",
+		Fields:   FieldPatterns,
+		Messages: builtinMessages,
+	}
+}
+
+// LoadSpec reads and validates a JSON spec.
+func LoadSpec(r io.Reader) (*Spec, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	spec := &Spec{}
+	if err := dec.Decode(spec); err != nil {
+		return nil, err
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// LoadSpecYAML reads and validates a YAML spec.
+func LoadSpecYAML(r io.Reader) (*Spec, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+
+	spec := &Spec{}
+	if err := dec.Decode(spec); err != nil {
+		return nil, err
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// LoadSpecFile reads and validates a spec from a file, which is read as YAML
+// when its name ends in .yaml or .yml and as JSON otherwise.
+func LoadSpecFile(name string) (*Spec, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml":
+		return LoadSpecYAML(f)
+	}
+	return LoadSpec(f)
+}
+
+// Validate checks every field format with ParseFormat and every message
+// structure, and returns all problems found.
+func (s *Spec) Validate() error {
+	var errs []error
+
+	errs = append(errs, validateFields("", s.Fields)...)
+
+	for _, mt := range slices.Sorted(maps.Keys(s.Messages)) {
+		ms := s.Messages[mt]
+		if ms == nil {
+			errs = append(errs, errors.New("Message MT"+mt+" is empty"))
+			continue
+		}
+		errs = append(errs, validateFields(mt, ms.Fields)...)
+
+		seqs := map[string]bool{}
+		for _, seq := range ms.Sequences {
+			if seqs[seq.Name] {
+				errs = append(errs, errors.New("Message MT"+mt+" has sequence '"+seq.Name+"' more than once"))
+			}
+			seqs[seq.Name] = true
+
+			for _, fld := range seq.Fields {
+				if !specTag.MatchString(fld.Tag) || (len(fld.Options) > 0 && len(fld.Tag) != 2) {
+					errs = append(errs, errors.New("Message MT"+mt+" has an invalid tag '"+fld.Tag+"'"))
+				}
+				for _, opt := range fld.Options {
+					if !specOption.MatchString(opt) {
+						errs = append(errs, errors.New("Message MT"+mt+" has an invalid option '"+opt+"' for tag "+fld.Tag))
+					}
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func validateFields(mt string, flds map[string]map[string]string) []error {
+	var errs []error
+	for _, tag := range slices.Sorted(maps.Keys(flds)) {
+		ptn := flds[tag]
+		var err error
+		for _, k := range slices.Sorted(maps.Keys(ptn)) {
+			if k != "pattern" && k != "fieldNames" && err == nil {
+				err = errors.New("unknown key '" + k + "'")
+			}
+		}
+		if err == nil && len(ptn["pattern"]) == 0 {
+			err = errors.New("no pattern")
+		}
+		if err == nil {
+			var f *Format
+			if f, err = ParseFormat(ptn["pattern"], ptn["fieldNames"]); err == nil && len(ptn["fieldNames"]) > 0 {
+				if n := len(formatNames(ptn["fieldNames"])); n > len(f.Components()) {
+					err = errors.New(strconv.Itoa(n) + " names for " + strconv.Itoa(len(f.Components())) + " components")
+				}
+			}
+		}
+		if err != nil {
+			if len(mt) > 0 {
+				err = errors.New("Message MT" + mt + " field " + tag + ": " + err.Error())
+			} else {
+				err = errors.New("Field " + tag + ": " + err.Error())
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Install validates the spec and registers its formats and structures with
+// r, replacing those already registered for the same message type and tag.
+func (s *Spec) Install(r *Registry) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	s.install(r)
+	return nil
+}
+
+func (s *Spec) install(r *Registry) {
+	for tag, ptn := range s.Fields {
+		r.set("", tag, ptn)
+	}
+	for mt, ms := range s.Messages {
+		for tag, ptn := range ms.Fields {
+			r.set(mt, tag, ptn)
+		}
+		if len(ms.Sequences) > 0 {
+			r.setStructure(mt, ms)
+		}
+	}
+}
+
+// Tags returns the tags allowed by the field, such as 50A, 50F and 50K.
+func (f *FieldSpec) Tags() []string {
+	if len(f.Options) == 0 {
+		return []string{f.Tag}
+	}
+	tags := make([]string, len(f.Options))
+	for i, opt := range f.Options {
+		tags[i] = f.Tag + opt
+	}
+	return tags
+}
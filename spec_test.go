@@ -0,0 +1,61 @@
+package mtparser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuiltinSpecValid(t *testing.T) {
+	if err := BuiltinSpec().Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadSpecYAML(t *testing.T) {
+	in := `name: inhouse
+version: SR2024
+fields:
+  72: {pattern: "6*35x", fieldNames: "(Narrative)"}
+messages:
+  103:
+    sequences:
+      - mandatory: true
+        fields:
+          - {tag: "20", mandatory: true}
+          - {tag: "59", options: ["", A, F], mandatory: true}
+`
+	spec, err := LoadSpecYAML(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := spec.Fields["72"]["pattern"]; got != "6*35x" {
+		t.Errorf("72 pattern = %q", got)
+	}
+	fld := spec.Messages["103"].Sequences[0].Fields[1]
+	if got := strings.Join(fld.Tags(), ","); got != "59,59A,59F" {
+		t.Errorf("59 tags = %s", got)
+	}
+
+	if _, err := LoadSpecYAML(strings.NewReader(in + "bogus: 1\n")); err == nil {
+		t.Error("expected an error for an unknown key")
+	}
+}
+
+func TestSpecFieldErrors(t *testing.T) {
+	tests := []struct {
+		ptn  map[string]string
+		want string
+	}{
+		{map[string]string{"fieldNames": "(Narrative)"}, "Field 72: no pattern"},
+		{map[string]string{"pattern": "6*35x", "fieldnames": "(Narrative)"}, "Field 72: unknown key 'fieldnames'"},
+		{map[string]string{"pattern": "6*35x", "fieldNames": "(Narrative)(Code)"}, "Field 72: 2 names for 1 components"},
+		{map[string]string{"pattern": "6*35q"}, "Field 72: Invalid component at position 4 of format 6*35q"},
+	}
+	for _, tt := range tests {
+		spec := &Spec{Name: "test", Fields: map[string]map[string]string{"72": tt.ptn}}
+		err := spec.Validate()
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("Validate(%v) = %v, want %s", tt.ptn, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package mtparser
+
+import (
+	"errors"
+	"math/big"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+var statementLine = regexp.MustCompile(`^([0-9]{6})([0-9]{4})?(R?[CD])([A-Z])?([0-9][0-9,]{0,14})([A-Z])([0-9A-Z]{3})([^\n]*?)(?://([^\n]{0,16}))?(?:\n([^\n]{0,34}))?$`)
+
+var statementTypes = []string{"940", "942", "950"}
+
+// Balance is a decoded balance field such as 60F, 62M, 64 or 65.
+type Balance struct {
+	Tag  string
+	Mark string
+	Date time.Time
+	Money
+}
+
+// Signed returns the balance amount, negative for debit balances.
+func (b *Balance) Signed() *big.Rat {
+	if b.Mark == "D" {
+		return new(big.Rat).Neg(b.Value)
+	}
+	return new(big.Rat).Set(b.Value)
+}
+
+// Intermediate reports whether the balance is an intermediate balance of a
+// statement split over several messages.
+func (b *Balance) Intermediate() bool {
+	return len(b.Tag) == 3 && b.Tag[2] == 'M'
+}
+
+// StatementLine is a decoded field 61 with the field 86 that followed it.
+// Mark is C, D or, for reversals, RC and RD.
+type StatementLine struct {
+	ValueDate         time.Time
+	EntryDate         time.Time
+	Mark              string
+	FundsCode         string
+	Amount            *big.Rat
+	TransactionType   string
+	CustomerReference string
+	BankReference     string
+	Supplementary     string
+	Information       string
+}
+
+// Signed returns the amount of the line as it affects the balance. Credits
+// and reversals of debits are positive.
+func (l *StatementLine) Signed() *big.Rat {
+	if l.Mark == "D" || l.Mark == "RC" {
+		return new(big.Rat).Neg(l.Amount)
+	}
+	return new(big.Rat).Set(l.Amount)
+}
+
+// EntrySummary is a decoded field 90C or 90D of a MT942.
+type EntrySummary struct {
+	Count int
+	Money
+}
+
+// Statement is a customer statement from a MT940, MT942 or MT950. Balances
+// and summaries are nil when absent. Pages counts the messages the statement
+// was assembled from.
+type Statement struct {
+	Type             string
+	Pages            int
+	Reference        string
+	RelatedReference string
+	Account          string
+	AccountBIC       string
+	StatementNumber  string
+	SequenceNumber   string
+	Opening          *Balance
+	Lines            []StatementLine
+	Closing          *Balance
+	ClosingAvailable *Balance
+	ForwardAvailable []Balance
+	Information      string
+	FloorLimits      []Balance
+	DateTime         string
+	Debits           *EntrySummary
+	Credits          *EntrySummary
+}
+
+// NewStatement builds a statement from a parsed MT940, MT942 or MT950.
+func NewStatement(m *Message) (*Statement, error) {
+	mt := m.Map["2"]["type"].Val
+	if !slices.Contains(statementTypes, mt) {
+		return nil, errors.New("Message type MT" + mt + " is not a statement")
+	}
+	if err := m.ParseBody(); err != nil {
+		return nil, err
+	}
+
+	st := &Statement{Type: mt, Pages: 1}
+	var lst *StatementLine
+
+	for _, fld := range m.Body {
+		var err error
+
+		switch fld.Key {
+		case "20":
+			st.Reference = fld.Val
+		case "21":
+			st.RelatedReference = fld.Val
+		case "25":
+			st.Account = fld.Val
+		case "25P":
+			st.Account, st.AccountBIC, _ = strings.Cut(fld.Val, "\n")
+		case "28C":
+			st.StatementNumber = fld.Det["StatementNumber"]
+			st.SequenceNumber = fld.Det["SequenceNumber"]
+		case "13D":
+			st.DateTime = fld.Val
+		case "34F":
+			var b *Balance
+			if b, err = newBalance(fld); err == nil {
+				st.FloorLimits = append(st.FloorLimits, *b)
+			}
+		case "60F", "60M":
+			st.Opening, err = newBalance(fld)
+		case "61":
+			var ln *StatementLine
+			if ln, err = newStatementLine(fld); err == nil {
+				st.Lines = append(st.Lines, *ln)
+				lst = &st.Lines[len(st.Lines)-1]
+			}
+		case "86":
+			if lst != nil {
+				lst.Information = fld.Val
+			} else {
+				st.Information = fld.Val
+			}
+		case "62F", "62M":
+			st.Closing, err = newBalance(fld)
+		case "64":
+			st.ClosingAvailable, err = newBalance(fld)
+		case "65":
+			var b *Balance
+			if b, err = newBalance(fld); err == nil {
+				st.ForwardAvailable = append(st.ForwardAvailable, *b)
+			}
+		case "90C":
+			st.Credits, err = newEntrySummary(fld)
+		case "90D":
+			st.Debits, err = newEntrySummary(fld)
+		}
+
+		if err != nil {
+			return nil, errors.New("Field " + fld.Key + ": " + err.Error())
+		}
+
+		// Only the 86 directly after a 61 belongs to that line.
+		if fld.Key != "61" {
+			lst = nil
+		}
+	}
+
+	return st, nil
+}
+
+func newBalance(fld Node) (*Balance, error) {
+	b := &Balance{Tag: fld.Key, Mark: fld.Det["Mark"]}
+
+	var err error
+	if d, ok := fld.Det["Date"]; ok {
+		if b.Date, err = ParseDate(d); err != nil {
+			return nil, err
+		}
+	}
+	if b.Money, err = money(fld.Det["Currency"], fld.Det["Amount"]); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func newEntrySummary(fld Node) (*EntrySummary, error) {
+	var err error
+	es := &EntrySummary{}
+
+	for _, c := range fld.Det["Number"] {
+		es.Count = es.Count*10 + int(c-'0')
+	}
+	if es.Money, err = money(fld.Det["Currency"], fld.Det["Amount"]); err != nil {
+		return nil, err
+	}
+	return es, nil
+}
+
+func newStatementLine(fld Node) (*StatementLine, error) {
+	mtc := statementLine.FindStringSubmatch(fld.Val)
+	if mtc == nil {
+		return nil, errors.New("Invalid statement line '" + fld.Val + "'")
+	}
+
+	var err error
+	ln := &StatementLine{
+		Mark:              mtc[3],
+		FundsCode:         mtc[4],
+		TransactionType:   mtc[6] + mtc[7],
+		CustomerReference: mtc[8],
+		BankReference:     mtc[9],
+		Supplementary:     mtc[10],
+	}
+
+	if ln.ValueDate, err = ParseDate(mtc[1]); err != nil {
+		return nil, err
+	}
+	if len(mtc[2]) > 0 {
+		if ln.EntryDate, err = entryDate(ln.ValueDate, mtc[2]); err != nil {
+			return nil, err
+		}
+	}
+	if ln.Amount, err = ParseAmount(mtc[5]); err != nil {
+		return nil, err
+	}
+
+	return ln, nil
+}
+
+// entryDate resolves a MMDD entry date to the year that puts it closest to
+// the value date, as entries may be booked across a year end.
+func entryDate(val time.Time, mmdd string) (time.Time, error) {
+	d, err := time.Parse("0102", mmdd)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var best time.Time
+	for y := val.Year() - 1; y <= val.Year()+1; y++ {
+		c := time.Date(y, d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+		if best.IsZero() || c.Sub(val).Abs() < best.Sub(val).Abs() {
+			best = c
+		}
+	}
+	return best, nil
+}
@@ -0,0 +1,144 @@
+package mtparser
+
+import "regexp"
+
+var (
+	refSplit     = regexp.MustCompile(`^([0-9]{6})([0-9A-Z]{12})([0-9]{4})([0-9]{6})$`)
+	trailerSplit = regexp.MustCompile(`^([0-9]{4})?(.{28})?$`)
+	mrfSplit     = regexp.MustCompile(`^([0-9]{6})([0-9]{4})(.{28})$`)
+)
+
+// MessageReference is a message input or output reference (MIR or MOR).
+type MessageReference struct {
+	Date      string
+	LTAddress string
+	Session   string
+	Sequence  string
+}
+
+// TrailerReference is the optional date, time and message reference carried
+// by the PDE, PDM, SYS and MRF trailers.
+type TrailerReference struct {
+	Date      string
+	Time      string
+	Reference *MessageReference
+}
+
+// Trailer is the decoded block 5 of a message. Reference trailers are nil
+// when absent.
+type Trailer struct {
+	CHK  string
+	MAC  string
+	PAC  string
+	PDE  *TrailerReference
+	PDM  *TrailerReference
+	DLM  bool
+	TNG  bool
+	SYS  *TrailerReference
+	MRF  *TrailerReference
+	Tags map[string]string
+}
+
+// SystemBlock is the decoded S block added by Alliance.
+type SystemBlock struct {
+	SPD  bool
+	SAC  bool
+	COP  string
+	MAN  string
+	MDG  string
+	Tags map[string]string
+}
+
+// Trailer decodes block 5, or returns nil when the message has none.
+func (m *Message) Trailer() *Trailer {
+	tgs := blockTags(m.Map["5"])
+	if tgs == nil {
+		return nil
+	}
+
+	t := &Trailer{
+		CHK:  tgs["CHK"],
+		MAC:  tgs["MAC"],
+		PAC:  tgs["PAC"],
+		Tags: tgs,
+	}
+	_, t.DLM = tgs["DLM"]
+	_, t.TNG = tgs["TNG"]
+
+	if v, ok := tgs["PDE"]; ok {
+		t.PDE = trailerReference(v)
+	}
+	if v, ok := tgs["PDM"]; ok {
+		t.PDM = trailerReference(v)
+	}
+	if v, ok := tgs["SYS"]; ok {
+		t.SYS = trailerReference(v)
+	}
+	if v, ok := tgs["MRF"]; ok {
+		t.MRF = &TrailerReference{}
+		if mtc := mrfSplit.FindStringSubmatch(v); mtc != nil {
+			t.MRF.Date = mtc[1]
+			t.MRF.Time = mtc[2]
+			t.MRF.Reference = messageReference(mtc[3])
+		}
+	}
+
+	return t
+}
+
+// PossibleDuplicate reports whether the message carries a PDE or PDM trailer.
+func (t *Trailer) PossibleDuplicate() bool {
+	return t.PDE != nil || t.PDM != nil
+}
+
+// SystemBlock decodes the S block, or returns nil when the message has none.
+func (m *Message) SystemBlock() *SystemBlock {
+	tgs := blockTags(m.Map["S"])
+	if tgs == nil {
+		return nil
+	}
+
+	b := &SystemBlock{
+		COP:  tgs["COP"],
+		MAN:  tgs["MAN"],
+		MDG:  tgs["MDG"],
+		Tags: tgs,
+	}
+	_, b.SPD = tgs["SPD"]
+	_, b.SAC = tgs["SAC"]
+
+	return b
+}
+
+func blockTags(blk map[string]Node) map[string]string {
+	if blk == nil {
+		return nil
+	}
+	tgs := map[string]string{}
+	for k, v := range blk {
+		tgs[k] = v.Val
+	}
+	return tgs
+}
+
+func trailerReference(v string) *TrailerReference {
+	r := &TrailerReference{}
+	if mtc := trailerSplit.FindStringSubmatch(v); mtc != nil {
+		r.Time = mtc[1]
+		r.Reference = messageReference(mtc[2])
+	}
+	return r
+}
+
+func messageReference(v string) *MessageReference {
+	mtc := refSplit.FindStringSubmatch(v)
+	if mtc == nil {
+		return nil
+	}
+	return &MessageReference{
+		Date:      mtc[1],
+		LTAddress: mtc[2],
+		Session:   mtc[3],
+		Sequence:  mtc[4],
+	}
+}
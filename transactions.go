@@ -0,0 +1,63 @@
+package mtparser
+
+import "errors"
+
+// repetitive describes how the text block of a multiple transaction message
+// is split. Each transaction of sequence B opens with start. Sequence C opens
+// with closer, which may also appear once in each transaction when shared.
+type repetitive struct {
+	start  string
+	closer string
+	shared bool
+}
+
+var repetitiveSequences = map[string]repetitive{
+	"101": {start: "21"},
+	"102": {start: "21", closer: "32A"},
+	"104": {start: "21", closer: "32B", shared: true},
+	"107": {start: "21", closer: "32B", shared: true},
+	"201": {start: "20"},
+	"203": {start: "20"},
+}
+
+// Split returns the text block of a multiple transaction message as a root
+// sequence holding sequence A, one sequence B per transaction and sequence C
+// when present.
+func (m *Message) Split() (*Sequence, error) {
+	mt := m.Map["2"]["type"].Val
+	rs, ok := repetitiveSequences[mt]
+	if !ok {
+		return nil, errors.New("MT" + mt + " has no repetitive sequence")
+	}
+
+	cur := &Sequence{Name: "A"}
+	root := &Sequence{Sequences: []*Sequence{cur}}
+	seen := map[string]bool{}
+
+	for _, fld := range m.Body {
+		switch {
+		case fld.Key == rs.start && cur.Name != "C":
+			cur = &Sequence{Name: "B"}
+			root.Sequences = append(root.Sequences, cur)
+			seen = map[string]bool{}
+		case fld.Key == rs.closer && cur.Name == "B" && (!rs.shared || seen[fld.Key]):
+			cur = &Sequence{Name: "C"}
+			root.Sequences = append(root.Sequences, cur)
+		}
+
+		seen[fld.Key] = true
+		cur.Fields = append(cur.Fields, fld)
+	}
+
+	return root, nil
+}
+
+// Transactions returns the sequence B of each transaction of a MT101, MT102,
+// MT104, MT107, MT201 or MT203.
+func (m *Message) Transactions() ([]*Sequence, error) {
+	root, err := m.Split()
+	if err != nil {
+		return nil, err
+	}
+	return root.Find("B"), nil
+}
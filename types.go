@@ -6,13 +6,35 @@ type ParserMap map[string]map[string]Node
 
 type Parser struct {
 	scanner.Scanner
+	Message
 	blk       Block
-	Blocks    []Block
-	Map       ParserMap
+	tag       string
+	errs      ErrorList
+	cr        *crReader
 	ErrPrefix string
+	// Tolerant makes Parse record errors and resume at the next block or
+	// field instead of stopping at the first problem.
+	Tolerant bool
+}
+
+// Message holds the blocks of a single parsed MT message. Body keeps every
+// field of the text block in order, including repeated tags which only
+// appear once in Map, as do generic fields with different qualifiers.
+// Original is set by Reader on ACK and NAK messages to the message that
+// followed them. Registry holds the field patterns used by ParseBody. Without
+// one, Release names the standards release to use, or is AutoRelease to
+// select it by date, and DefaultRegistry is used otherwise.
+type Message struct {
+	Blocks   []Block
+	Map      ParserMap
+	Body     []Node
+	Original *Message
+	Registry *Registry
+	Release  string
 }
 
 type Node struct {
+	Key string            `json:"-" bson:"-"`
 	Val string            `json:"value" bson:"value"`
 	Blk int               `json:"-" bson:"-"`
 	Ind int               `json:"-" bson:"-"`
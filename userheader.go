@@ -0,0 +1,159 @@
+package mtparser
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+)
+
+// userHeaderTags lists the block 3 tags in the order they must appear.
+var userHeaderTags = []string{"103", "113", "108", "119", "423", "106", "424", "111", "121", "115", "165", "433", "434"}
+
+var userHeaderFormats = map[string]*regexp.Regexp{
+	"103": regexp.MustCompile(`^[A-Z]{3}$`),
+	"113": regexp.MustCompile(`^.{4}$`),
+	"108": regexp.MustCompile(`^.{1,16}$`),
+	"119": regexp.MustCompile(`^[0-9A-Z]{1,8}$`),
+	"423": regexp.MustCompile(`^[0-9]{12}[0-9]{0,3}$`),
+	"106": regexp.MustCompile(`^[0-9A-Z]{28}$`),
+	"424": regexp.MustCompile(`^.{1,16}$`),
+	"111": regexp.MustCompile(`^[0-9]{3}$`),
+	"121": regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`),
+	"115": regexp.MustCompile(`^.{1,32}$`),
+	"165": regexp.MustCompile(`^/[0-9A-Z]{3}/.{0,34}$`),
+	"433": regexp.MustCompile(`^/(AOK|FPO|NOK)/.{0,20}$`),
+	"434": regexp.MustCompile(`^/(FPO|NOK)/.{0,20}$`),
+}
+
+// validationFlags lists the values of tag 119 allowed per message type.
+var validationFlags = map[string][]string{
+	"101": {"RFDD"},
+	"102": {"STP"},
+	"103": {"STP", "REMIT"},
+	"104": {"RFDD"},
+	"202": {"COV"},
+	"205": {"COV"},
+}
+
+var codedSplit = regexp.MustCompile(`^/([^/]*)/?(.*)$`)
+
+// uetrRequired lists the message types that must carry tag 121.
+var uetrRequired = []string{"103", "202", "205"}
+
+// CodedInfo is a /code/information value as used by tags 165, 433 and 434.
+type CodedInfo struct {
+	Code string
+	Info string
+}
+
+// UserHeader is the decoded block 3 of a message.
+type UserHeader struct {
+	ServiceIdentifier     string    // 103
+	BankingPriority       string    // 113
+	MUR                   string    // 108
+	ValidationFlag        string    // 119
+	BalanceCheckpoint     string    // 423
+	MIR                   string    // 106
+	RelatedReference      string    // 424
+	ServiceTypeIdentifier string    // 111
+	UETR                  string    // 121
+	Addressee             string    // 115
+	PaymentReleaseInfo    CodedInfo // 165
+	SanctionsScreening    CodedInfo // 433
+	PaymentControls       CodedInfo // 434
+	Tags                  map[string]string
+	order                 []string
+	mt                    string
+}
+
+// UserHeader decodes block 3, or returns nil when the message has none.
+func (m *Message) UserHeader() *UserHeader {
+	blk, ok := m.Map["3"]
+	if !ok {
+		return nil
+	}
+
+	h := &UserHeader{Tags: map[string]string{}}
+	for k, v := range blk {
+		h.Tags[k] = v.Val
+		h.order = append(h.order, k)
+	}
+	slices.SortFunc(h.order, func(a, b string) int {
+		return blk[a].Ind - blk[b].Ind
+	})
+
+	h.ServiceIdentifier = h.Tags["103"]
+	h.BankingPriority = h.Tags["113"]
+	h.MUR = h.Tags["108"]
+	h.ValidationFlag = h.Tags["119"]
+	h.BalanceCheckpoint = h.Tags["423"]
+	h.MIR = h.Tags["106"]
+	h.RelatedReference = h.Tags["424"]
+	h.ServiceTypeIdentifier = h.Tags["111"]
+	h.UETR = h.Tags["121"]
+	h.Addressee = h.Tags["115"]
+	h.PaymentReleaseInfo = codedInfo(h.Tags["165"])
+	h.SanctionsScreening = codedInfo(h.Tags["433"])
+	h.PaymentControls = codedInfo(h.Tags["434"])
+
+	if nd, ok := m.Map["2"]["type"]; ok {
+		h.mt = nd.Val
+	}
+	return h
+}
+
+// Validate checks the format of every tag, the tag order and the rules on
+// which tags may appear together.
+func (h *UserHeader) Validate() error {
+	var errs []error
+
+	last := -1
+	for _, k := range h.order {
+		ptn, ok := userHeaderFormats[k]
+		if !ok {
+			errs = append(errs, errors.New("Tag "+k+" is not allowed in the user header"))
+			continue
+		}
+		if !ptn.MatchString(h.Tags[k]) {
+			errs = append(errs, errors.New("Tag "+k+" has an invalid value '"+h.Tags[k]+"'"))
+		}
+		if i := slices.Index(userHeaderTags, k); i < last {
+			errs = append(errs, errors.New("Tag "+k+" is out of order"))
+		} else {
+			last = i
+		}
+	}
+
+	if h.has("111") && !h.has("121") {
+		errs = append(errs, errors.New("Tag 111 requires tag 121"))
+	}
+	if h.has("115") && !h.has("103") {
+		errs = append(errs, errors.New("Tag 115 is only allowed with tag 103"))
+	}
+	if h.has("165") && !h.has("103") {
+		errs = append(errs, errors.New("Tag 165 is only allowed with tag 103"))
+	}
+	if h.has("119") && len(h.mt) > 0 {
+		if !slices.Contains(validationFlags[h.mt], h.ValidationFlag) {
+			errs = append(errs, errors.New("Validation flag "+h.ValidationFlag+" is not allowed in MT"+h.mt))
+		}
+	}
+	if slices.Contains(uetrRequired, h.mt) && !h.has("121") {
+		errs = append(errs, errors.New("Tag 121 is mandatory in MT"+h.mt))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (h *UserHeader) has(k string) bool {
+	_, ok := h.Tags[k]
+	return ok
+}
+
+func codedInfo(v string) CodedInfo {
+	mtc := codedSplit.FindStringSubmatch(v)
+	if mtc == nil {
+		return CodedInfo{Info: v}
+	}
+	return CodedInfo{Code: mtc[1], Info: mtc[2]}
+}
@@ -0,0 +1,216 @@
+package mtparser
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Kinds of Finding, usable with errors.Is.
+var (
+	ErrMissingField    = errors.New("missing mandatory field")
+	ErrUnexpectedField = errors.New("unexpected field")
+	ErrWrongOption     = errors.New("option not allowed")
+	ErrRepeatedField   = errors.New("field repeated")
+	ErrFieldOrder      = errors.New("field out of order")
+)
+
+// Finding is a problem with the structure of the text block. Index is the
+// position of the field in Body, or -1 for missing fields.
+type Finding struct {
+	Kind     error
+	Tag      string
+	Sequence string
+	Index    int
+}
+
+func (f *Finding) Error() string {
+	var msg string
+
+	switch f.Kind {
+	case ErrMissingField:
+		msg = "Mandatory field " + f.Tag + " is missing"
+	case ErrUnexpectedField:
+		msg = "Field " + f.Tag + " is not expected"
+	case ErrWrongOption:
+		msg = "Field " + f.Tag + " uses an option that is not allowed"
+	case ErrRepeatedField:
+		msg = "Field " + f.Tag + " may not be repeated"
+	case ErrFieldOrder:
+		msg = "Field " + f.Tag + " is out of order"
+	default:
+		msg = "Field " + f.Tag + " is invalid"
+	}
+
+	if len(f.Sequence) > 0 {
+		msg += " in sequence " + f.Sequence
+	}
+	if f.Index >= 0 {
+		msg += " (field " + strconv.Itoa(f.Index+1) + ")"
+	}
+	return msg
+}
+
+func (f *Finding) Unwrap() error {
+	return f.Kind
+}
+
+// Findings is returned by Validate and holds every problem found.
+type Findings []*Finding
+
+func (l Findings) Error() string {
+	switch len(l) {
+	case 0:
+		return "no findings"
+	case 1:
+		return l[0].Error()
+	}
+	return l[0].Error() + " (and " + strconv.Itoa(len(l)-1) + " more findings)"
+}
+
+func (l Findings) Unwrap() []error {
+	errs := make([]error, len(l))
+	for i, f := range l {
+		errs[i] = f
+	}
+	return errs
+}
+
+// Err returns nil when the list is empty and the list otherwise.
+func (l Findings) Err() error {
+	if len(l) == 0 {
+		return nil
+	}
+	return l
+}
+
+// specField is a field of a message structure with the sequence it is in.
+type specField struct {
+	seq *SequenceSpec
+	fld *FieldSpec
+	// first is set for the first field of its sequence.
+	first bool
+}
+
+// Validate checks the text block against the structure of the message type
+// in the registry of the message. It reports missing mandatory fields,
+// unexpected tags, options that are not allowed, repeated fields and fields
+// out of order, as Findings.
+func (m *Message) Validate() error {
+	reg, err := m.registry()
+	if err != nil {
+		return err
+	}
+	mt := m.messageType()
+	ms, ok := reg.Structure(mt)
+	if !ok {
+		return errors.New("No structure for message type MT" + mt)
+	}
+
+	var flds []specField
+	for _, seq := range ms.Sequences {
+		for i, fld := range seq.Fields {
+			flds = append(flds, specField{seq, fld, i == 0})
+		}
+	}
+
+	var fnd Findings
+	cnt := make([]int, len(flds))
+	seen := map[*SequenceSpec]bool{}
+
+	// closeSeq reports the mandatory fields missing from the current
+	// occurrence of seq and resets its counts for the next one.
+	closeSeq := func(seq *SequenceSpec) {
+		for i, sf := range flds {
+			if sf.seq != seq {
+				continue
+			}
+			if sf.fld.Mandatory && cnt[i] == 0 && (seen[seq] || seq.Mandatory) {
+				fnd = append(fnd, &Finding{Kind: ErrMissingField, Tag: specTagName(sf.fld), Sequence: seq.Name, Index: -1})
+			}
+			cnt[i] = 0
+		}
+	}
+
+	cur := 0
+	for idx, nd := range m.Body {
+		j := specIndex(flds, nd.Key, cur, len(flds))
+		if j == cur && cnt[j] > 0 && !flds[j].fld.Repeatable {
+			// A field already present moves on to the next place the tag
+			// is allowed, as the second 34F of a MT942.
+			if k := specIndex(flds, nd.Key, cur+1, len(flds)); k >= 0 {
+				j = k
+			}
+		}
+		if j < 0 {
+			j = specIndex(flds, nd.Key, 0, cur)
+		}
+		restart := j >= 0 && flds[j].first && flds[j].seq.Repeatable && cnt[j] > 0
+
+		switch {
+		case j >= cur && !restart:
+			if j == cur && cnt[j] > 0 && !flds[j].fld.Repeatable {
+				fnd = append(fnd, &Finding{Kind: ErrRepeatedField, Tag: nd.Key, Sequence: flds[j].seq.Name, Index: idx})
+			}
+			cnt[j]++
+			seen[flds[j].seq] = true
+			cur = j
+		case restart && flds[cur].seq == flds[j].seq:
+			// The next occurrence of a repeatable sequence.
+			closeSeq(flds[j].seq)
+			cnt[j]++
+			cur = j
+		case restart:
+			fnd = append(fnd, &Finding{Kind: ErrFieldOrder, Tag: nd.Key, Sequence: flds[j].seq.Name, Index: idx})
+		case j >= 0 && cnt[j] > 0 && !flds[j].fld.Repeatable:
+			fnd = append(fnd, &Finding{Kind: ErrRepeatedField, Tag: nd.Key, Sequence: flds[j].seq.Name, Index: idx})
+		case j >= 0:
+			fnd = append(fnd, &Finding{Kind: ErrFieldOrder, Tag: nd.Key, Sequence: flds[j].seq.Name, Index: idx})
+			cnt[j]++
+		case specOptionOf(flds, nd.Key):
+			fnd = append(fnd, &Finding{Kind: ErrWrongOption, Tag: nd.Key, Index: idx})
+		default:
+			fnd = append(fnd, &Finding{Kind: ErrUnexpectedField, Tag: nd.Key, Index: idx})
+		}
+	}
+
+	for _, seq := range ms.Sequences {
+		closeSeq(seq)
+	}
+
+	return fnd.Err()
+}
+
+// specIndex returns the first field in flds[from:to] that allows tag, or -1.
+func specIndex(flds []specField, tag string, from int, to int) int {
+	for i := from; i < to; i++ {
+		for _, t := range flds[i].fld.Tags() {
+			if t == tag {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// specOptionOf reports whether tag is an option of a field that takes
+// others, as 50B is of 50a.
+func specOptionOf(flds []specField, tag string) bool {
+	if len(tag) < 2 || len(tag) > 3 {
+		return false
+	}
+	for _, sf := range flds {
+		if len(sf.fld.Options) > 0 && sf.fld.Tag == tag[:2] {
+			return true
+		}
+	}
+	return false
+}
+
+// specTagName returns the tag of a field in SWIFT notation, as 50a for a
+// field with options.
+func specTagName(fld *FieldSpec) string {
+	if len(fld.Options) > 0 {
+		return fld.Tag + "a"
+	}
+	return fld.Tag
+}
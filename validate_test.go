@@ -0,0 +1,43 @@
+package mtparser
+
+import (
+	"errors"
+	"testing"
+)
+
+func textBlock(mt string, body string) string {
+	return "{1:F01AAAAGB2LAXXX0000000000}{2:I" + mt + "BBBBGB2LXXXXN}{4:\n" + body + "-}"
+}
+
+func TestValidateBuiltin(t *testing.T) {
+	tests := []struct {
+		mt   string
+		body string
+	}{
+		{"101", ":20:REF\n:28D:1/1\n:50H:/ACC\nNAME\n:30:240101\n:21:T1\n:32B:EUR10,\n:59:/ACC1\nNAME1\n:71A:SHA\n:21:T2\n:23E:CHQB\n:32B:EUR20,\n:59A:BKAUATWW\n:71A:OUR\n"},
+		{"102", ":20:REF\n:23:CREDIT\n:50K:NAME\n:71A:SHA\n:21:T1\n:32B:EUR10,\n:59:NAME1\n:21:T2\n:32B:EUR20,\n:59:NAME2\n:71G:EUR1,\n:32A:240101EUR30,\n:19:30,\n"},
+		{"104", ":20:REF\n:23E:RFDD\n:30:240101\n:21:T1\n:32B:EUR10,\n:59:/ACC1\nNAME1\n:21:T2\n:32B:EUR20,\n:59:/ACC2\nNAME2\n:32B:EUR30,\n:19:30,\n"},
+		{"205", ":20:REF\n:21:REL\n:32A:240101EUR30,\n:52A:BKAUATWW\n:58A:BKAUATWW\n"},
+		{"942", ":20:R\n:25:ACC\n:28C:1\n:34F:EURD10,\n:34F:EURC20,\n:13D:2401011200+0100\n:61:2401020102D100,50NTRFNONREF\n:86:X\n:61:2401020102D100,50NTRFNONREF\n:90D:2EUR201,\n:86:END\n"},
+		{"950", ":20:R\n:25:ACC\n:28C:1/1\n:60F:C231229EUR1000,00\n:61:2401020102D100,50NTRFNONREF\n:62F:C240102EUR899,50\n"},
+	}
+	for _, tt := range tests {
+		if err := parseMessage(t, textBlock(tt.mt, tt.body)).Validate(); err != nil {
+			t.Errorf("MT%s: %v", tt.mt, err)
+		}
+	}
+}
+
+func TestValidateFindings(t *testing.T) {
+	msg := parseMessage(t, textBlock("942", ":20:R\n:25:ACC\n:28C:1\n:34F:EURD10,\n:34F:EURC20,\n:34F:EURC30,\n:13D:2401011200+0100\n:62F:C240102EUR899,50\n"))
+	var fnd Findings
+	if !errors.As(msg.Validate(), &fnd) || len(fnd) != 2 {
+		t.Fatalf("Validate = %v, want 2 findings", fnd)
+	}
+	if !errors.Is(fnd[0], ErrRepeatedField) || fnd[0].Tag != "34F" {
+		t.Errorf("first finding = %v", fnd[0])
+	}
+	if !errors.Is(fnd[1], ErrUnexpectedField) || fnd[1].Tag != "62F" {
+		t.Errorf("second finding = %v", fnd[1])
+	}
+}